@@ -0,0 +1,54 @@
+package task
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"github.com/go-task/task/v3/internal/artifact"
+	"github.com/go-task/task/v3/internal/fingerprint"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// artifactKey returns the key under which the files generated by the given
+// task are stored in the output cache. An empty key means that the task cannot
+// be cached, either because caching is disabled or because the task does not
+// declare both sources and generates.
+func (e *Executor) artifactKey(t *ast.Task) (string, error) {
+	if e.artifactCache == nil || e.Dry {
+		return "", nil
+	}
+	if len(t.Sources) == 0 || len(t.Generates) == 0 {
+		return "", nil
+	}
+	if cmp.Or(t.Method, e.Taskfile.Method) == "none" {
+		return "", nil
+	}
+
+	checksum, err := fingerprint.NewChecksumChecker(e.TempDir.Fingerprint, true).Value(t)
+	if err != nil {
+		return "", err
+	}
+	return artifact.Key(t, e.Dir, fmt.Sprint(checksum))
+}
+
+func (e *Executor) restoreFromCache(ctx context.Context, t *ast.Task, key string) (bool, error) {
+	if key == "" {
+		return false, nil
+	}
+	return e.artifactCache.Restore(ctx, key, t.Dir)
+}
+
+func (e *Executor) storeInCache(ctx context.Context, t *ast.Task, key string) error {
+	if key == "" {
+		return nil
+	}
+	generates, err := fingerprint.Globs(t.Dir, t.Generates)
+	if err != nil {
+		return err
+	}
+	if len(generates) == 0 {
+		return nil
+	}
+	return e.artifactCache.Store(ctx, key, t.Dir, generates)
+}
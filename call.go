@@ -8,4 +8,7 @@ type Call struct {
 	Vars     *ast.Vars
 	Silent   bool
 	Indirect bool // True if the task was called by another task
+	// RetryAttempt is the number of the current retry of the task or command
+	// being run, or zero on the first attempt
+	RetryAttempt int
 }
@@ -0,0 +1,45 @@
+// cacheserver is a minimal remote cache server for Task that stores cache
+// entries in a directory on disk. It is intended for small teams and for
+// testing; larger setups can implement the same protocol on top of any HTTP
+// server or object storage.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/go-task/task/v3/internal/artifact"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "Address to listen on")
+	dir := flag.String("dir", "task-cache", "Directory to store cache entries in")
+	token := flag.String("token", "", "Require this bearer token in the Authorization header")
+	flag.Parse()
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	var handler http.Handler = artifact.NewFileServer(*dir)
+	if *token != "" {
+		handler = requireToken(handler, *token)
+	}
+
+	log.Printf("cacheserver: serving %s on %s", *dir, *addr)
+	log.Fatal(http.ListenAndServe(*addr, handler))
+}
+
+func requireToken(next http.Handler, token string) http.Handler {
+	expected := fmt.Sprintf("Bearer %s", token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != expected {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
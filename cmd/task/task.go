@@ -1,6 +1,7 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
@@ -13,10 +14,14 @@ import (
 	"github.com/go-task/task/v3"
 	"github.com/go-task/task/v3/args"
 	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/events"
 	"github.com/go-task/task/v3/internal/experiments"
 	"github.com/go-task/task/v3/internal/filepathext"
 	"github.com/go-task/task/v3/internal/flags"
+	"github.com/go-task/task/v3/internal/lint"
 	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/report"
+	"github.com/go-task/task/v3/internal/trace"
 	ver "github.com/go-task/task/v3/internal/version"
 	"github.com/go-task/task/v3/taskfile"
 	"github.com/go-task/task/v3/taskfile/ast"
@@ -34,6 +39,10 @@ func main() {
 			l.Errf(logger.Red, "%v\n", err)
 			os.Exit(err.TaskExitCode())
 		}
+		if err, ok := err.(*errors.TasksFailedError); ok && flags.ExitCode {
+			l.Errf(logger.Red, "%v\n", err)
+			os.Exit(err.TaskExitCode())
+		}
 		if err, ok := err.(errors.TaskError); ok {
 			l.Errf(logger.Red, "%v\n", err)
 			os.Exit(err.Code())
@@ -117,7 +126,41 @@ func run() error {
 		flags.WithExecutorOptions(),
 		task.ExecutorWithVersionCheck(true),
 	)
+	if flags.Events != "" {
+		w, err := events.Open(flags.Events)
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+		e.Options(task.ExecutorWithEvents(w))
+	}
+	if flags.ReportFile != "" {
+		f, err := os.Create(flags.ReportFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		e.Options(task.ExecutorWithReport(cmp.Or(flags.Report, report.FormatText), f))
+	}
+	if flags.JUnit != "" {
+		f, err := os.Create(flags.JUnit)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		e.Options(task.ExecutorWithJUnit(f))
+	}
+	if flags.Trace != "" {
+		tracer := trace.New()
+		e.Options(task.ExecutorWithTracer(tracer))
+		defer writeTrace(log, tracer, flags.Trace)
+	}
 	if err := e.Setup(); err != nil {
+		// Errors that point at a location in the Taskfile are reported like
+		// any other issue found by the linter
+		if issue, ok := lint.FromError(err); ok && flags.Lint {
+			return printLintIssues([]*lint.Issue{issue})
+		}
 		return err
 	}
 
@@ -135,6 +178,18 @@ func run() error {
 		return cache.Clear()
 	}
 
+	if flags.Lint {
+		return printLintIssues(e.Lint())
+	}
+
+	if flags.FingerprintList {
+		return e.ListFingerprints()
+	}
+
+	if flags.FingerprintClear != "" {
+		return e.ClearFingerprints(flags.FingerprintClear)
+	}
+
 	listOptions := task.NewListOptions(
 		flags.List,
 		flags.ListAll,
@@ -167,6 +222,11 @@ func run() error {
 
 	calls, globals = args.Parse(tasksAndVars...)
 
+	if flags.Graph != "" {
+		e.Taskfile.Vars.Merge(globals, nil)
+		return e.PrintTaskGraph(flags.Graph, calls...)
+	}
+
 	// If there are no calls, run the default task instead
 	if len(calls) == 0 {
 		calls = append(calls, &task.Call{Task: "default"})
@@ -189,9 +249,36 @@ func run() error {
 		return e.Status(ctx, calls...)
 	}
 
+	if flags.Explain {
+		return e.Explain(ctx, calls...)
+	}
+
 	return e.Run(ctx, calls...)
 }
 
+func printLintIssues(issues []*lint.Issue) error {
+	write := lint.Write
+	if flags.ListJson {
+		write = lint.WriteJSON
+	}
+	if err := write(os.Stdout, issues); err != nil {
+		return err
+	}
+	return lint.Err(issues)
+}
+
+func writeTrace(log *logger.Logger, tracer *trace.Tracer, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.Errf(logger.Red, "task: unable to write trace: %v\n", err)
+		return
+	}
+	defer f.Close()
+	if err := tracer.Write(f); err != nil {
+		log.Errf(logger.Red, "task: unable to write trace: %v\n", err)
+	}
+}
+
 func getArgs() ([]string, string, error) {
 	var (
 		args          = pflag.Args()
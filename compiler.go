@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/go-task/task/v3/internal/filepathext"
 	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/internal/trace"
 	"github.com/go-task/task/v3/internal/version"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
@@ -27,6 +29,7 @@ type Compiler struct {
 	TaskfileVars *ast.Vars
 
 	Logger *logger.Logger
+	Tracer *trace.Tracer
 
 	dynamicCache   map[string]string
 	muDynamicCache sync.Mutex
@@ -165,6 +168,9 @@ func (c *Compiler) HandleDynamicVar(v ast.Var, dir string, e []string) (string,
 		dir = v.Dir
 	}
 
+	_, span := c.Tracer.Start(context.Background(), *v.Sh, "var", nil)
+	defer span.End()
+
 	var stdout bytes.Buffer
 	opts := &execext.RunCommandOptions{
 		Command: *v.Sh,
@@ -217,8 +223,10 @@ func (c *Compiler) getSpecialVars(t *ast.Task, call *Call) (map[string]string, e
 	}
 	if call != nil {
 		allVars["ALIAS"] = call.Task
+		allVars["RETRY_ATTEMPT"] = strconv.Itoa(call.RetryAttempt)
 	} else {
 		allVars["ALIAS"] = ""
+		allVars["RETRY_ATTEMPT"] = ""
 	}
 
 	return allVars, nil
@@ -1,11 +1,27 @@
 package task
 
-func (e *Executor) acquireConcurrencyLimit() func() {
+import (
+	"github.com/go-task/task/v3/internal/output"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+func (e *Executor) acquireConcurrencyLimit(t *ast.Task) func() {
 	if e.concurrencySemaphore == nil {
 		return emptyFunc
 	}
 
-	e.concurrencySemaphore <- struct{}{}
+	select {
+	case e.concurrencySemaphore <- struct{}{}:
+	default:
+		// The task has to wait for a free slot, which some outputs show
+		if queuer, ok := e.Output.(output.Queuer); ok {
+			dequeue := queuer.Queue(t)
+			e.concurrencySemaphore <- struct{}{}
+			dequeue()
+		} else {
+			e.concurrencySemaphore <- struct{}{}
+		}
+	}
 	return func() {
 		<-e.concurrencySemaphore
 	}
@@ -22,4 +38,13 @@ func (e *Executor) releaseConcurrencyLimit() func() {
 	}
 }
 
+// pauseOutput stops outputs that draw to the terminal from doing so until the
+// returned function is called, so that the user can interact with it.
+func (e *Executor) pauseOutput() func() {
+	if pauser, ok := e.Output.(output.Pauser); ok {
+		return pauser.Pause()
+	}
+	return emptyFunc
+}
+
 func emptyFunc() {}
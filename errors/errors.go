@@ -21,6 +21,7 @@ const (
 	CodeTaskfileNetworkTimeout
 	CodeTaskfileInvalid
 	CodeTaskfileCycle
+	CodeTaskfileLint
 )
 
 // Task related exit codes
@@ -33,6 +34,8 @@ const (
 	CodeTaskCancelled
 	CodeTaskMissingRequiredVars
 	CodeTaskNotAllowedVars
+	CodeTaskCycle
+	CodeTaskTimeout
 )
 
 // TaskError extends the standard error interface with a Code method. This code will
@@ -2,7 +2,9 @@ package errors
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"mvdan.cc/sh/v3/interp"
 )
@@ -52,6 +54,42 @@ func (err *TaskRunError) TaskExitCode() int {
 	return err.Code()
 }
 
+// TasksFailedError is returned when more than one task failed, which can only
+// happen when tasks are allowed to keep going after another task failed.
+type TasksFailedError struct {
+	Errors []error
+}
+
+func (err *TasksFailedError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("task: %d tasks failed:", len(err.Errors)))
+	for _, e := range err.Errors {
+		if runErr, ok := e.(*TaskRunError); ok {
+			builder.WriteString(fmt.Sprintf("\n  - %q: %v", runErr.TaskName, runErr.Err))
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("\n  - %s", strings.TrimPrefix(e.Error(), "task: ")))
+	}
+	return builder.String()
+}
+
+func (err *TasksFailedError) Code() int {
+	return CodeTaskRunError
+}
+
+// TaskExitCode returns the exit code of the first task that failed.
+func (err *TasksFailedError) TaskExitCode() int {
+	for _, e := range err.Errors {
+		if runErr, ok := e.(*TaskRunError); ok {
+			return runErr.TaskExitCode()
+		}
+		if taskErr, ok := e.(TaskError); ok {
+			return taskErr.Code()
+		}
+	}
+	return err.Code()
+}
+
 // TaskInternalError when the user attempts to invoke a task that is internal.
 type TaskInternalError struct {
 	TaskName string
@@ -141,6 +179,22 @@ func (err *TaskCancelledNoTerminalError) Code() int {
 	return CodeTaskCancelled
 }
 
+// TaskCancelledBySignalError is returned when tasks are cancelled because Task
+// received a signal, such as SIGINT or SIGTERM. It is also the cause of the
+// cancelled context, which is how the signal is forwarded to the commands that
+// are running.
+type TaskCancelledBySignalError struct {
+	Signal os.Signal
+}
+
+func (err *TaskCancelledBySignalError) Error() string {
+	return fmt.Sprintf(`task: Cancelled by signal %q`, err.Signal)
+}
+
+func (err *TaskCancelledBySignalError) Code() int {
+	return CodeTaskCancelled
+}
+
 // TaskMissingRequiredVarsError is returned when a task is missing required variables.
 
 type MissingVar struct {
@@ -200,3 +254,50 @@ func (err *TaskNotAllowedVarsError) Error() string {
 func (err *TaskNotAllowedVarsError) Code() int {
 	return CodeTaskNotAllowedVars
 }
+
+// TaskCycleError is returned when tasks depend on or call each other in a cycle
+// that would never end.
+type TaskCycleError struct {
+	// Cycle is the list of tasks in the cycle. The first task is repeated at
+	// the end.
+	Cycle []string
+	// Locations holds the location of the definition of each task in the
+	// cycle, if known.
+	Locations map[string]string
+}
+
+func (err *TaskCycleError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("task: Cycle detected between tasks: %s", strings.Join(err.Cycle, " -> ")))
+	for _, name := range err.Cycle[:len(err.Cycle)-1] {
+		if location, ok := err.Locations[name]; ok {
+			builder.WriteString(fmt.Sprintf("\n  - %s: %s", name, location))
+		}
+	}
+	return builder.String()
+}
+
+func (err *TaskCycleError) Code() int {
+	return CodeTaskCycle
+}
+
+// TaskTimeoutError is returned when a task or one of its commands runs for
+// longer than its timeout.
+type TaskTimeoutError struct {
+	TaskName string
+	// Cmd is the command that timed out, if the timeout was set on the
+	// command rather than on the task.
+	Cmd     string
+	Timeout time.Duration
+}
+
+func (err *TaskTimeoutError) Error() string {
+	if err.Cmd != "" {
+		return fmt.Sprintf(`task: Command %q in task %q timed out after %s`, err.Cmd, err.TaskName, err.Timeout)
+	}
+	return fmt.Sprintf(`task: Task %q timed out after %s`, err.TaskName, err.Timeout)
+}
+
+func (err *TaskTimeoutError) Code() int {
+	return CodeTaskTimeout
+}
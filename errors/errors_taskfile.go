@@ -192,3 +192,28 @@ func (err TaskfileCycleError) Error() string {
 func (err TaskfileCycleError) Code() int {
 	return CodeTaskfileCycle
 }
+
+// TaskfileLintError is returned when linting a Taskfile finds at least one
+// error. The issues themselves are printed by the linter.
+type TaskfileLintError struct {
+	Errors   int
+	Warnings int
+}
+
+func (err *TaskfileLintError) Error() string {
+	return fmt.Sprintf("task: Found %s and %s in the Taskfile",
+		pluralize(err.Errors, "error"),
+		pluralize(err.Warnings, "warning"),
+	)
+}
+
+func (err *TaskfileLintError) Code() int {
+	return CodeTaskfileLint
+}
+
+func pluralize(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
@@ -10,9 +10,15 @@ import (
 	"github.com/puzpuzpuz/xsync/v3"
 	"github.com/sajari/fuzzy"
 
+	"github.com/go-task/task/v3/internal/artifact"
+	"github.com/go-task/task/v3/internal/events"
+	"github.com/go-task/task/v3/internal/junit"
 	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/internal/output"
+	"github.com/go-task/task/v3/internal/report"
 	"github.com/go-task/task/v3/internal/sort"
+	"github.com/go-task/task/v3/internal/timings"
+	"github.com/go-task/task/v3/internal/trace"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
 
@@ -32,6 +38,7 @@ type (
 		Download    bool
 		Offline     bool
 		Timeout     time.Duration
+		Strict      bool
 		Watch       bool
 		Verbose     bool
 		Silent      bool
@@ -40,18 +47,32 @@ type (
 		Dry         bool
 		Summary     bool
 		Parallel    bool
+		KeepGoing   bool
 		Color       bool
 		Concurrency int
 		Interval    time.Duration
+		KillTimeout time.Duration
+
+		Cache        bool
+		CacheMaxSize int64
+		RemoteCache  artifact.Remote
+		Timings      bool
+		Report       string
+		Timestamps   string
 
 		// I/O
 		Stdin  io.Reader
 		Stdout io.Writer
 		Stderr io.Writer
+		Events io.Writer
+		// ReportWriter is where the report is written to. Defaults to Stderr.
+		ReportWriter io.Writer
+		JUnit        io.Writer
 
 		// Internal
 		Taskfile           *ast.Taskfile
 		Logger             *logger.Logger
+		Tracer             *trace.Tracer
 		Compiler           *Compiler
 		Output             output.Output
 		OutputStyle        ast.Output
@@ -61,12 +82,19 @@ type (
 
 		fuzzyModel *fuzzy.Model
 
+		artifactCache        *artifact.Cache
+		events               *events.Stream
+		timings              *timings.Recorder
+		report               *report.Recorder
+		junit                *junit.Recorder
 		concurrencySemaphore chan struct{}
 		taskCallCount        map[string]*int32
 		mkdirMutexMap        map[string]*sync.Mutex
 		executionHashes      map[string]context.Context
 		executionHashesMutex sync.Mutex
 		watchedDirs          *xsync.MapOf[string, bool]
+		interrupted          context.Context
+		interrupt            context.CancelCauseFunc
 	}
 	TempDir struct {
 		Remote      string
@@ -184,6 +212,25 @@ func ExecutorWithTimeout(timeout time.Duration) ExecutorOption {
 	}
 }
 
+// ExecutorWithStrict makes the [Executor] fail to read a Taskfile that contains
+// keys that are not part of the Taskfile schema. By default, unknown keys only
+// print a warning.
+func ExecutorWithStrict(strict bool) ExecutorOption {
+	return func(e *Executor) {
+		e.Strict = strict
+	}
+}
+
+// ExecutorWithKillTimeout sets how long the commands that are running are
+// given to stop after Task forwards them a signal before they are killed.
+// Tasks can set their own grace period with kill_timeout. By default, commands
+// are killed after 15 seconds.
+func ExecutorWithKillTimeout(timeout time.Duration) ExecutorOption {
+	return func(e *Executor) {
+		e.KillTimeout = timeout
+	}
+}
+
 // ExecutorWithWatch tells the [Executor] to keep running in the background and
 // watch for changes to the fingerprint of the tasks that are run. When changes
 // are detected, a new task run is triggered.
@@ -239,6 +286,16 @@ func ExecutorWithParallel(parallel bool) ExecutorOption {
 	}
 }
 
+// ExecutorWithKeepGoing tells the [Executor] to let tasks that don't depend on
+// a failed task finish, instead of cancelling them, and to report every task
+// that failed at the end. Tasks can override this for their own deps with
+// deps_failure.
+func ExecutorWithKeepGoing(keepGoing bool) ExecutorOption {
+	return func(e *Executor) {
+		e.KeepGoing = keepGoing
+	}
+}
+
 // ExecutorWithColor tells the [Executor] whether or not to output using
 // colorized strings.
 func ExecutorWithColor(color bool) ExecutorOption {
@@ -263,6 +320,76 @@ func ExecutorWithInterval(interval time.Duration) ExecutorOption {
 	}
 }
 
+// ExecutorWithCache tells the [Executor] to store the files generated by tasks
+// with sources and generates in a local cache and to restore them from the
+// cache instead of running the task when an identical run has already
+// happened.
+func ExecutorWithCache(cache bool) ExecutorOption {
+	return func(e *Executor) {
+		e.Cache = cache
+	}
+}
+
+// ExecutorWithCacheMaxSize sets the maximum size in bytes of the local output
+// cache. When the cache grows larger than this, the least recently used
+// entries are evicted. A size of zero means the cache is unbounded.
+func ExecutorWithCacheMaxSize(maxSize int64) ExecutorOption {
+	return func(e *Executor) {
+		e.CacheMaxSize = maxSize
+	}
+}
+
+// ExecutorWithRemoteCache sets a remote cache that the [Executor] will
+// download generated files from when they are not in the local cache and
+// upload newly generated files to. Setting a remote cache enables the local
+// cache.
+func ExecutorWithRemoteCache(remote artifact.Remote) ExecutorOption {
+	return func(e *Executor) {
+		e.RemoteCache = remote
+	}
+}
+
+// ExecutorWithTimings tells the [Executor] to print a table with the time each
+// task took, how long it waited for the concurrency limit and its
+// dependencies, and the critical path through the dependency graph once the
+// run has finished.
+func ExecutorWithTimings(timings bool) ExecutorOption {
+	return func(e *Executor) {
+		e.Timings = timings
+	}
+}
+
+// ExecutorWithReport tells the [Executor] to write a report of every task that
+// ran, was up-to-date, was deduplicated, was skipped or failed once the run has
+// finished. The format is either [report.FormatText] or [report.FormatJSON].
+// The report is written to w, or to the [Executor]'s standard error if w is
+// nil.
+func ExecutorWithReport(format string, w io.Writer) ExecutorOption {
+	return func(e *Executor) {
+		e.Report = format
+		e.ReportWriter = w
+	}
+}
+
+// ExecutorWithJUnit sets an [io.Writer] that the [Executor] will write a JUnit
+// XML report to once the run has finished. Every task that was executed is
+// reported as a test case, together with the output of its commands.
+func ExecutorWithJUnit(w io.Writer) ExecutorOption {
+	return func(e *Executor) {
+		e.JUnit = w
+	}
+}
+
+// ExecutorWithTimestamps tells the [Executor] to prepend a timestamp to every
+// line printed by commands. The format is either [output.TimestampsWall] or
+// [output.TimestampsElapsed]. Only the interleaved and prefixed output styles
+// support timestamps.
+func ExecutorWithTimestamps(format string) ExecutorOption {
+	return func(e *Executor) {
+		e.Timestamps = format
+	}
+}
+
 // ExecutorWithOutputStyle sets the output style of the [Executor]. By default,
 // the output style is set to the style defined in the Taskfile.
 func ExecutorWithOutputStyle(outputStyle ast.Output) ExecutorOption {
@@ -311,6 +438,23 @@ func ExecutorWithIO(rw io.ReadWriter) ExecutorOption {
 	}
 }
 
+// ExecutorWithEvents sets an [io.Writer] that the [Executor] will write a
+// stream of JSON events to as it runs tasks and commands. Each event is written
+// on its own line.
+func ExecutorWithEvents(w io.Writer) ExecutorOption {
+	return func(e *Executor) {
+		e.Events = w
+	}
+}
+
+// ExecutorWithTracer sets the [trace.Tracer] that the [Executor] will record
+// spans for tasks, commands and other expensive operations to.
+func ExecutorWithTracer(tracer *trace.Tracer) ExecutorOption {
+	return func(e *Executor) {
+		e.Tracer = tracer
+	}
+}
+
 // ExecutorWithVersionCheck tells the [Executor] whether or not to check the
 // version of
 func ExecutorWithVersionCheck(enableVersionCheck bool) ExecutorOption {
@@ -0,0 +1,186 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/taskgraph"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// PrintTaskGraph prints the graph of the given tasks and everything they
+// depend on or call in the given format. If no calls are given, the graph of
+// every task in the Taskfile is printed.
+func (e *Executor) PrintTaskGraph(format string, calls ...*Call) error {
+	g, err := e.TaskGraph(calls...)
+	if err != nil {
+		return err
+	}
+	return g.Write(e.Stdout, format)
+}
+
+// TaskGraph returns the graph of the given tasks and everything they depend on
+// or call. Tasks are compiled in order to resolve variables in task names and
+// to expand for loops, just like they would be before running them. If no
+// calls are given, the graph contains every task in the Taskfile.
+func (e *Executor) TaskGraph(calls ...*Call) (*taskgraph.Graph, error) {
+	if len(calls) == 0 {
+		for name := range e.Taskfile.Tasks.Keys(nil) {
+			calls = append(calls, &Call{Task: name})
+		}
+	}
+
+	g := taskgraph.New()
+	visited := map[string]bool{}
+	for _, call := range calls {
+		if _, err := e.addToTaskGraph(g, visited, call); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+func (e *Executor) addToTaskGraph(g *taskgraph.Graph, visited map[string]bool, call *Call) (string, error) {
+	// The same task can have different deps depending on the variables it is
+	// called with, so it is visited once for each set of variables.
+	key := call.Task
+	if call.Vars != nil {
+		key += fmt.Sprint(call.Vars.ToCacheMap())
+	}
+
+	t, err := e.CompiledTask(call)
+	if err != nil {
+		return "", err
+	}
+	name := e.taskGraphName(call, t)
+	g.AddNode(&taskgraph.Node{
+		Name:     name,
+		Desc:     t.Desc,
+		Location: taskGraphLocation(t.Location),
+	})
+	if visited[key] {
+		return name, nil
+	}
+	visited[key] = true
+
+	for _, d := range t.Deps {
+		to, err := e.addToTaskGraph(g, visited, &Call{Task: d.Task, Vars: d.Vars})
+		if err != nil {
+			return "", err
+		}
+		g.AddEdge(name, to, taskgraph.EdgeDep)
+	}
+	for _, c := range t.Cmds {
+		if c.Task == "" {
+			continue
+		}
+		to, err := e.addToTaskGraph(g, visited, &Call{Task: c.Task, Vars: c.Vars})
+		if err != nil {
+			return "", err
+		}
+		g.AddEdge(name, to, taskgraph.EdgeCall)
+	}
+	return name, nil
+}
+
+// taskGraphName returns the name of the node for the given call. Calls to
+// wildcard tasks keep the name they were called with, so that each call is
+// shown separately, while aliases are resolved to the name of the task.
+func (e *Executor) taskGraphName(call *Call, t *ast.Task) string {
+	for _, match := range e.FindMatchingTasks(call) {
+		if len(match.Wildcards) > 0 {
+			return call.Task
+		}
+	}
+	return t.Task
+}
+
+func taskGraphLocation(l *ast.Location) *taskgraph.Location {
+	if l == nil {
+		return nil
+	}
+	return &taskgraph.Location{
+		Taskfile: l.Taskfile,
+		Line:     l.Line,
+		Column:   l.Column,
+	}
+}
+
+// checkTaskCycles returns a [errors.TaskCycleError] if any of the given calls
+// can reach a cycle of tasks that would never end.
+func (e *Executor) checkTaskCycles(calls ...*Call) error {
+	g := e.staticTaskGraph()
+	var from []string
+	for _, call := range calls {
+		if t, err := e.GetTask(&Call{Task: call.Task}); err == nil {
+			from = append(from, t.Task)
+		}
+	}
+	cycle := g.Cycle(from...)
+	if cycle == nil {
+		return nil
+	}
+	locations := make(map[string]string, len(cycle))
+	for _, name := range cycle {
+		if node, ok := g.Node(name); ok && node.Location != nil {
+			locations[name] = fmt.Sprintf("%s:%d:%d", node.Location.Taskfile, node.Location.Line, node.Location.Column)
+		}
+	}
+	return &errors.TaskCycleError{Cycle: cycle, Locations: locations}
+}
+
+// staticTaskGraph returns the graph of tasks without compiling them. Only the
+// edges that are always followed when a task runs are added, so that any
+// cycle in the graph is one that would never end:
+//   - deps always run before anything else in a task, so every dep is added.
+//   - calls from cmds are only added when they don't pass any variables and
+//     the called task has no status, sources or preconditions that could stop
+//     the recursion.
+//
+// Tasks that only run on some platforms and calls to task names that contain
+// templates, which can't be resolved without compiling the task, are left out.
+func (e *Executor) staticTaskGraph() *taskgraph.Graph {
+	g := taskgraph.New()
+	resolve := func(name string) (string, bool) {
+		if strings.Contains(name, "{{") {
+			return "", false
+		}
+		t, err := e.GetTask(&Call{Task: name})
+		if err != nil || len(t.Platforms) > 0 {
+			return "", false
+		}
+		return t.Task, true
+	}
+
+	for t := range e.Taskfile.Tasks.Values(nil) {
+		g.AddNode(&taskgraph.Node{
+			Name:     t.Task,
+			Desc:     t.Desc,
+			Location: taskGraphLocation(t.Location),
+		})
+		for _, d := range t.Deps {
+			if d == nil {
+				continue
+			}
+			if to, ok := resolve(d.Task); ok {
+				g.AddEdge(t.Task, to, taskgraph.EdgeDep)
+			}
+		}
+		for _, c := range t.Cmds {
+			if c == nil || c.Task == "" || c.For != nil || len(c.Platforms) > 0 || c.Vars.Len() > 0 {
+				continue
+			}
+			to, ok := resolve(c.Task)
+			if !ok {
+				continue
+			}
+			called, ok := e.Taskfile.Tasks.Get(to)
+			if !ok || len(called.Status) > 0 || len(called.Sources) > 0 || len(called.Preconditions) > 0 {
+				continue
+			}
+			g.AddEdge(t.Task, to, taskgraph.EdgeCall)
+		}
+	}
+	return g
+}
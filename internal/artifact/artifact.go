@@ -0,0 +1,491 @@
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/hashstructure/v2"
+	"github.com/zeebo/xxh3"
+
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+const (
+	objectsDir = "objects"
+	entriesDir = "entries"
+)
+
+type (
+	// CacheOption is a function that configures a [Cache].
+	CacheOption func(*Cache)
+	// A Cache is a local content-addressed store for the files generated by a
+	// task. Each entry is keyed by the compiled definition of a task and the
+	// checksum of its sources. The files themselves are stored as blobs which
+	// are named after the hash of their contents, so identical files are only
+	// stored once.
+	Cache struct {
+		dir     string
+		maxSize int64
+		remote  Remote
+		mutex   sync.Mutex
+	}
+	// A Manifest describes the files stored for a single cache entry.
+	Manifest struct {
+		Files []File `json:"files"`
+	}
+	// A File is a single file in a [Manifest]. Its path is relative to the
+	// directory of the task that generated it.
+	File struct {
+		Path string      `json:"path"`
+		Hash string      `json:"hash"`
+		Size int64       `json:"size"`
+		Mode os.FileMode `json:"mode"`
+	}
+)
+
+// NewCache creates a new [Cache] in the given directory and applies the given
+// functional options to it.
+func NewCache(dir string, opts ...CacheOption) *Cache {
+	c := &Cache{
+		dir:     dir,
+		maxSize: 0,
+		remote:  nil,
+	}
+	c.Options(opts...)
+	return c
+}
+
+// Options loops through the given [CacheOption] functions and applies them to
+// the [Cache].
+func (c *Cache) Options(opts ...CacheOption) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// CacheWithMaxSize sets the maximum size in bytes of the [Cache]. Whenever the
+// total size of the cache exceeds it, the least recently used entries are
+// evicted. By default, the cache is unbounded.
+func CacheWithMaxSize(maxSize int64) CacheOption {
+	return func(c *Cache) {
+		c.maxSize = maxSize
+	}
+}
+
+// CacheWithRemote sets a [Remote] that the [Cache] will download entries from
+// when they are missing locally and upload new entries to.
+func CacheWithRemote(remote Remote) CacheOption {
+	return func(c *Cache) {
+		c.remote = remote
+	}
+}
+
+// Key returns the cache key for the given compiled task and its sources
+// checksum. Only the parts of the task that affect what is generated are
+// considered, so the key is stable between runs.
+func Key(t *ast.Task, root string, sourcesHash string) (string, error) {
+	dir, err := filepath.Rel(root, t.Dir)
+	if err != nil {
+		dir = t.Dir
+	}
+	cmds := make([]string, 0, len(t.Cmds))
+	for _, cmd := range t.Cmds {
+		if cmd == nil {
+			continue
+		}
+		cmds = append(cmds, cmd.Cmd, cmd.Task)
+	}
+	var env map[string]any
+	if t.Env != nil {
+		env = t.Env.ToCacheMap()
+	}
+	h, err := hashstructure.Hash(struct {
+		Task      string
+		Dir       string
+		Cmds      []string
+		Sources   []*ast.Glob
+		Generates []*ast.Glob
+		Env       map[string]any
+		Sum       string
+	}{
+		Task:      t.Task,
+		Dir:       filepath.ToSlash(dir),
+		Cmds:      cmds,
+		Sources:   t.Sources,
+		Generates: t.Generates,
+		Env:       env,
+		Sum:       sourcesHash,
+	}, hashstructure.FormatV2, nil)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%016x", h), nil
+}
+
+// Restore copies the files stored under the given key into dir. If the entry
+// does not exist locally, it is downloaded from the remote, if any. It returns
+// false if there is no entry for the key or if any of its blobs are missing.
+func (c *Cache) Restore(ctx context.Context, key string, dir string) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	manifest, err := c.readManifest(key)
+	if os.IsNotExist(err) && c.remote != nil {
+		manifest, err = c.download(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, f := range manifest.Files {
+		if _, err := os.Stat(c.objectPath(f.Hash)); err != nil {
+			return false, nil
+		}
+	}
+	for _, f := range manifest.Files {
+		if !validPath(f.Path) {
+			return false, fmt.Errorf("task: invalid path %q in cache entry %q", f.Path, key)
+		}
+		dst := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return false, err
+		}
+		if err := copyFile(c.objectPath(f.Hash), dst, f.Mode.Perm()); err != nil {
+			return false, err
+		}
+	}
+
+	// Mark the entry as recently used
+	now := time.Now()
+	_ = os.Chtimes(c.entryPath(key), now, now)
+	return true, c.evict()
+}
+
+// Store saves the given files under the given key and uploads them to the
+// remote, if any. The files will be restored relative to dir.
+func (c *Cache) Store(ctx context.Context, key string, dir string, files []string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var manifest Manifest
+	for _, f := range files {
+		rel, err := filepath.Rel(dir, f)
+		if err != nil {
+			return err
+		}
+		if !validPath(filepath.ToSlash(rel)) {
+			return fmt.Errorf("task: generated file %q is outside of %q and can't be cached", f, dir)
+		}
+		info, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+		hash, err := hashFile(f)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(c.objectPath(hash)); os.IsNotExist(err) {
+			if err := os.MkdirAll(filepath.Dir(c.objectPath(hash)), 0o755); err != nil {
+				return err
+			}
+			if err := copyFile(f, c.objectPath(hash), 0o644); err != nil {
+				return err
+			}
+		}
+		manifest.Files = append(manifest.Files, File{
+			Path: filepath.ToSlash(rel),
+			Hash: hash,
+			Size: info.Size(),
+			Mode: info.Mode().Perm(),
+		})
+	}
+	if err := c.writeManifest(key, &manifest); err != nil {
+		return err
+	}
+	if c.remote != nil {
+		if err := c.upload(ctx, key, &manifest); err != nil {
+			return err
+		}
+	}
+	return c.evict()
+}
+
+// download fetches the manifest for the given key and any blobs that are
+// missing locally from the remote and adds them to the local cache.
+func (c *Cache) download(ctx context.Context, key string) (*Manifest, error) {
+	manifest, err := c.remote.GetManifest(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := manifest.validate(); err != nil {
+		return nil, fmt.Errorf("%w in remote cache entry %q", err, key)
+	}
+	for _, f := range manifest.Files {
+		if _, err := os.Stat(c.objectPath(f.Hash)); err == nil {
+			continue
+		}
+		if err := c.downloadBlob(ctx, f.Hash); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.writeManifest(key, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (c *Cache) downloadBlob(ctx context.Context, hash string) error {
+	r, err := c.remote.GetBlob(ctx, hash)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	path := c.objectPath(hash)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := writeFile(tmp, r, 0o644); err != nil {
+		return err
+	}
+	// Never trust the remote to return the blob we asked for
+	if actual, err := hashFile(tmp); err != nil || actual != hash {
+		os.Remove(tmp)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("task: remote cache returned blob %q for %q", actual, hash)
+	}
+	return os.Rename(tmp, path)
+}
+
+// upload sends the blobs of the given entry to the remote followed by its
+// manifest, so that other clients never see an entry with missing blobs.
+func (c *Cache) upload(ctx context.Context, key string, manifest *Manifest) error {
+	for _, f := range manifest.Files {
+		if err := c.uploadBlob(ctx, f); err != nil {
+			return err
+		}
+	}
+	return c.remote.PutManifest(ctx, key, manifest)
+}
+
+func (c *Cache) uploadBlob(ctx context.Context, f File) error {
+	r, err := os.Open(c.objectPath(f.Hash))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return c.remote.PutBlob(ctx, f.Hash, r, f.Size)
+}
+
+// Clear removes every entry from the cache.
+func (c *Cache) Clear() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return os.RemoveAll(c.dir)
+}
+
+// evict removes the least recently used entries until the size of the cache
+// is below its maximum size and then removes any blobs that are no longer
+// referenced by an entry.
+func (c *Cache) evict() error {
+	if c.maxSize <= 0 {
+		return nil
+	}
+
+	type entry struct {
+		key      string
+		manifest *Manifest
+		used     time.Time
+	}
+
+	dirEntries, err := os.ReadDir(filepath.Join(c.dir, entriesDir))
+	if err != nil {
+		return err
+	}
+	entries := make([]entry, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		key := de.Name()
+		manifest, err := c.readManifest(key)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{key: key, manifest: manifest, used: info.ModTime()})
+	}
+
+	// Count each blob once, even if it is shared between entries
+	refs := map[string]int{}
+	sizes := map[string]int64{}
+	var total int64
+	for _, e := range entries {
+		for _, f := range e.manifest.Files {
+			if refs[f.Hash] == 0 {
+				total += f.Size
+				sizes[f.Hash] = f.Size
+			}
+			refs[f.Hash]++
+		}
+	}
+	if total <= c.maxSize {
+		return nil
+	}
+
+	// Oldest entries first
+	slices.SortFunc(entries, func(a, b entry) int {
+		return a.used.Compare(b.used)
+	})
+	for _, e := range entries {
+		if total <= c.maxSize {
+			break
+		}
+		if err := os.Remove(c.entryPath(e.key)); err != nil {
+			return err
+		}
+		for _, f := range e.manifest.Files {
+			refs[f.Hash]--
+			if refs[f.Hash] == 0 {
+				total -= sizes[f.Hash]
+				if err := os.Remove(c.objectPath(f.Hash)); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Cache) readManifest(key string) (*Manifest, error) {
+	b, err := os.ReadFile(c.entryPath(key))
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+func (c *Cache) writeManifest(key string, manifest *Manifest) error {
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(c.dir, entriesDir), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.entryPath(key), b, 0o644)
+}
+
+func (c *Cache) entryPath(key string) string {
+	return filepath.Join(c.dir, entriesDir, key)
+}
+
+func (c *Cache) objectPath(hash string) string {
+	return filepath.Join(c.dir, objectsDir, hash[:2], hash)
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := xxh3.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	sum := h.Sum128()
+	return fmt.Sprintf("%016x%016x", sum.Hi, sum.Lo), nil
+}
+
+// validate checks that every file of the manifest has a valid hash and a path
+// that stays inside the directory it is restored to, so that a manifest from
+// an untrusted source can't be used to overwrite arbitrary files.
+func (m *Manifest) validate() error {
+	for _, f := range m.Files {
+		if !validHash(f.Hash) {
+			return fmt.Errorf("task: invalid hash %q", f.Hash)
+		}
+		if !validPath(f.Path) {
+			return fmt.Errorf("task: invalid path %q", f.Path)
+		}
+	}
+	return nil
+}
+
+// validPath reports whether the given slash-separated path is relative and
+// does not point outside of the directory it is relative to.
+func validPath(path string) bool {
+	p := filepath.FromSlash(path)
+	if path == "" || strings.HasPrefix(path, "/") || filepath.IsAbs(p) || filepath.VolumeName(p) != "" {
+		return false
+	}
+	p = filepath.Clean(p)
+	return p != "." && p != ".." && !strings.HasPrefix(p, ".."+string(filepath.Separator))
+}
+
+func validHash(hash string) bool {
+	if len(hash) != 32 {
+		return false
+	}
+	for _, r := range hash {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	// Write to a temporary file first so a partially written file is never
+	// left behind in its final location.
+	tmp := dst + ".tmp"
+	if err := writeFile(tmp, in, mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package artifact_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/internal/artifact"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestStoreAndRestore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cache := artifact.NewCache(filepath.Join(dir, ".task", "artifacts"))
+
+	writeFile(t, filepath.Join(dir, "out", "a.txt"), "a")
+	writeFile(t, filepath.Join(dir, "b.txt"), "b")
+	require.NoError(t, cache.Store(context.Background(), "key", dir, []string{
+		filepath.Join(dir, "out", "a.txt"),
+		filepath.Join(dir, "b.txt"),
+	}))
+
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "out")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "b.txt")))
+
+	restored, err := cache.Restore(context.Background(), "key", dir)
+	require.NoError(t, err)
+	assert.True(t, restored)
+
+	b, err := os.ReadFile(filepath.Join(dir, "out", "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(b))
+	b, err = os.ReadFile(filepath.Join(dir, "b.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "b", string(b))
+
+	restored, err = cache.Restore(context.Background(), "missing", dir)
+	require.NoError(t, err)
+	assert.False(t, restored)
+}
+
+func TestEviction(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cache := artifact.NewCache(filepath.Join(dir, ".task", "artifacts"), artifact.CacheWithMaxSize(10))
+
+	writeFile(t, filepath.Join(dir, "old.txt"), "123456")
+	require.NoError(t, cache.Store(context.Background(), "old", dir, []string{filepath.Join(dir, "old.txt")}))
+
+	// Make sure the second entry is more recent than the first one
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, ".task", "artifacts", "entries", "old"), past, past))
+
+	writeFile(t, filepath.Join(dir, "new.txt"), "654321")
+	require.NoError(t, cache.Store(context.Background(), "new", dir, []string{filepath.Join(dir, "new.txt")}))
+
+	restored, err := cache.Restore(context.Background(), "old", dir)
+	require.NoError(t, err)
+	assert.False(t, restored)
+
+	restored, err = cache.Restore(context.Background(), "new", dir)
+	require.NoError(t, err)
+	assert.True(t, restored)
+}
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	task := &ast.Task{
+		Task:      "build",
+		Dir:       "/project/sub",
+		Cmds:      []*ast.Cmd{{Cmd: "go build"}},
+		Sources:   []*ast.Glob{{Glob: "*.go"}},
+		Generates: []*ast.Glob{{Glob: "app"}},
+	}
+
+	k1, err := artifact.Key(task, "/project", "abc")
+	require.NoError(t, err)
+	k2, err := artifact.Key(task, "/project", "abc")
+	require.NoError(t, err)
+	assert.Equal(t, k1, k2)
+
+	k3, err := artifact.Key(task, "/project", "def")
+	require.NoError(t, err)
+	assert.NotEqual(t, k1, k3)
+
+	task.Cmds[0].Cmd = "go build -race"
+	k4, err := artifact.Key(task, "/project", "abc")
+	require.NoError(t, err)
+	assert.NotEqual(t, k1, k4)
+}
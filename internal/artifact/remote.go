@@ -0,0 +1,180 @@
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultRemoteTimeout is how long a request to an [HTTPRemote], including
+// reading its response, may take by default.
+const DefaultRemoteTimeout = time.Minute
+
+// ErrNotFound is returned by a [Remote] when the requested manifest or blob
+// does not exist.
+var ErrNotFound = errors.New("task: not found in remote cache")
+
+var errHashMismatch = errors.New("task: content does not match hash")
+
+// A Remote is a shared cache that entries can be downloaded from and uploaded
+// to. Manifests are stored under the key of their entry and blobs under the
+// hash of their contents.
+type Remote interface {
+	GetManifest(ctx context.Context, key string) (*Manifest, error)
+	PutManifest(ctx context.Context, key string, manifest *Manifest) error
+	GetBlob(ctx context.Context, hash string) (io.ReadCloser, error)
+	PutBlob(ctx context.Context, hash string, r io.Reader, size int64) error
+}
+
+// An HTTPRemote is a [Remote] that talks to a cache server over HTTP. Manifests
+// are read and written with GET and PUT requests to {url}/ac/{key} and blobs
+// to {url}/cas/{hash}.
+type HTTPRemote struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// An HTTPRemoteOption is a functional option for [NewHTTPRemote].
+type HTTPRemoteOption func(*HTTPRemote)
+
+// NewHTTPRemote creates a new [HTTPRemote] for the given base URL. The given
+// headers are added to every request. Environment variables in header values
+// are expanded, so credentials do not need to be written to disk.
+func NewHTTPRemote(url string, headers map[string]string, opts ...HTTPRemoteOption) *HTTPRemote {
+	expanded := make(map[string]string, len(headers))
+	for k, v := range headers {
+		expanded[k] = os.ExpandEnv(v)
+	}
+	r := &HTTPRemote{
+		url:     strings.TrimSuffix(url, "/"),
+		headers: expanded,
+		client:  &http.Client{Timeout: DefaultRemoteTimeout},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// HTTPRemoteWithTimeout sets how long a request to the [HTTPRemote], including
+// reading its response, may take. A zero timeout keeps the default of
+// [DefaultRemoteTimeout].
+func HTTPRemoteWithTimeout(timeout time.Duration) HTTPRemoteOption {
+	return func(r *HTTPRemote) {
+		if timeout > 0 {
+			r.client.Timeout = timeout
+		}
+	}
+}
+
+// IsTimeout reports whether err was caused by a request to a remote cache that
+// took too long.
+func IsTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (r *HTTPRemote) GetManifest(ctx context.Context, key string) (*Manifest, error) {
+	body, err := r.get(ctx, "ac/"+key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(body).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("task: invalid manifest %q in remote cache: %w", key, err)
+	}
+	return &manifest, nil
+}
+
+func (r *HTTPRemote) PutManifest(ctx context.Context, key string, manifest *Manifest) error {
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return r.put(ctx, "ac/"+key, bytes.NewReader(b), int64(len(b)))
+}
+
+func (r *HTTPRemote) GetBlob(ctx context.Context, hash string) (io.ReadCloser, error) {
+	return r.get(ctx, "cas/"+hash)
+}
+
+func (r *HTTPRemote) PutBlob(ctx context.Context, hash string, body io.Reader, size int64) error {
+	return r.put(ctx, "cas/"+hash, body, size)
+}
+
+func (r *HTTPRemote) get(ctx context.Context, path string) (io.ReadCloser, error) {
+	req, err := r.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("task: remote cache returned %s for GET %s", resp.Status, path)
+	}
+	return resp.Body, nil
+}
+
+func (r *HTTPRemote) put(ctx context.Context, path string, body io.Reader, size int64) error {
+	req, err := r.newRequest(ctx, http.MethodPut, path, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("task: remote cache returned %s for PUT %s", resp.Status, path)
+	}
+	return nil
+}
+
+func (r *HTTPRemote) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, r.url+"/"+path, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
+// ReadOnly wraps the given [Remote] so that entries are only ever downloaded
+// from it. Uploads are silently discarded.
+func ReadOnly(remote Remote) Remote {
+	return readOnlyRemote{remote}
+}
+
+type readOnlyRemote struct {
+	Remote
+}
+
+func (readOnlyRemote) PutManifest(context.Context, string, *Manifest) error {
+	return nil
+}
+
+func (readOnlyRemote) PutBlob(context.Context, string, io.Reader, int64) error {
+	return nil
+}
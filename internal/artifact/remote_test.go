@@ -0,0 +1,222 @@
+package artifact_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/internal/artifact"
+)
+
+func TestRemote(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(artifact.NewFileServer(t.TempDir()))
+	defer server.Close()
+	remote := artifact.NewHTTPRemote(server.URL, nil)
+
+	// Store the entry from one machine...
+	dir1 := t.TempDir()
+	cache1 := artifact.NewCache(filepath.Join(dir1, ".task", "artifacts"), artifact.CacheWithRemote(remote))
+	writeFile(t, filepath.Join(dir1, "out", "a.txt"), "a")
+	require.NoError(t, cache1.Store(context.Background(), "abc", dir1, []string{filepath.Join(dir1, "out", "a.txt")}))
+
+	// ...and restore it on another one
+	dir2 := t.TempDir()
+	cache2 := artifact.NewCache(filepath.Join(dir2, ".task", "artifacts"), artifact.CacheWithRemote(remote))
+	restored, err := cache2.Restore(context.Background(), "abc", dir2)
+	require.NoError(t, err)
+	assert.True(t, restored)
+
+	b, err := os.ReadFile(filepath.Join(dir2, "out", "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(b))
+
+	restored, err = cache2.Restore(context.Background(), "def", dir2)
+	require.NoError(t, err)
+	assert.False(t, restored)
+}
+
+func TestRemoteReadOnly(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(artifact.NewFileServer(t.TempDir()))
+	defer server.Close()
+	remote := artifact.ReadOnly(artifact.NewHTTPRemote(server.URL, nil))
+
+	dir := t.TempDir()
+	cache := artifact.NewCache(filepath.Join(dir, ".task", "artifacts"), artifact.CacheWithRemote(remote))
+	writeFile(t, filepath.Join(dir, "a.txt"), "a")
+	require.NoError(t, cache.Store(context.Background(), "abc", dir, []string{filepath.Join(dir, "a.txt")}))
+
+	_, err := remote.GetManifest(context.Background(), "abc")
+	assert.ErrorIs(t, err, artifact.ErrNotFound)
+}
+
+func TestRemoteHeaders(t *testing.T) {
+	t.Setenv("TASK_CACHE_TOKEN", "secret")
+
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	remote := artifact.NewHTTPRemote(server.URL, map[string]string{
+		"Authorization": "Bearer ${TASK_CACHE_TOKEN}",
+	})
+	_, err := remote.GetManifest(context.Background(), "abc")
+	assert.ErrorIs(t, err, artifact.ErrNotFound)
+	assert.Equal(t, "Bearer secret", auth)
+}
+
+func TestFileServerRejectsInvalidBlobs(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(artifact.NewFileServer(t.TempDir()))
+	defer server.Close()
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/cas/00000000000000000000000000000000", want: http.StatusBadRequest},
+		{path: "/cas/../../etc/passwd", want: http.StatusNotFound},
+		{path: "/other/abc", want: http.StatusNotFound},
+	}
+	for _, test := range tests {
+		req, err := http.NewRequest(http.MethodPut, server.URL+test.path, strings.NewReader("content"))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, test.want, resp.StatusCode, test.path)
+	}
+}
+
+// tamperedRemote serves a modified manifest for every key, like a malicious or
+// compromised cache server would.
+type tamperedRemote struct {
+	artifact.Remote
+	tamper func(*artifact.Manifest)
+}
+
+func (r *tamperedRemote) GetManifest(ctx context.Context, key string) (*artifact.Manifest, error) {
+	manifest, err := r.Remote.GetManifest(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	r.tamper(manifest)
+	return manifest, nil
+}
+
+func TestRemoteRejectsUnsafeManifests(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(artifact.NewFileServer(t.TempDir()))
+	defer server.Close()
+	remote := artifact.NewHTTPRemote(server.URL, nil)
+
+	dir1 := t.TempDir()
+	cache1 := artifact.NewCache(filepath.Join(dir1, ".task", "artifacts"), artifact.CacheWithRemote(remote))
+	writeFile(t, filepath.Join(dir1, "a.txt"), "a")
+	require.NoError(t, cache1.Store(context.Background(), "abc", dir1, []string{filepath.Join(dir1, "a.txt")}))
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "parent", path: "../a.txt"},
+		{name: "nested parent", path: "out/../../a.txt"},
+		{name: "absolute", path: filepath.ToSlash(filepath.Join(t.TempDir(), "a.txt"))},
+		{name: "root", path: "/a.txt"},
+		{name: "dot", path: "."},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parent := t.TempDir()
+			dir2 := filepath.Join(parent, "project")
+			cache2 := artifact.NewCache(filepath.Join(dir2, ".task", "artifacts"), artifact.CacheWithRemote(&tamperedRemote{
+				Remote: remote,
+				tamper: func(m *artifact.Manifest) { m.Files[0].Path = test.path },
+			}))
+			restored, err := cache2.Restore(context.Background(), "abc", dir2)
+			require.ErrorContains(t, err, "invalid path")
+			assert.False(t, restored)
+			assert.NoFileExists(t, filepath.Join(parent, "a.txt"))
+		})
+	}
+
+	// Mode bits other than the permissions are never restored
+	dir3 := t.TempDir()
+	cache3 := artifact.NewCache(filepath.Join(dir3, ".task", "artifacts"), artifact.CacheWithRemote(&tamperedRemote{
+		Remote: remote,
+		tamper: func(m *artifact.Manifest) { m.Files[0].Mode = os.ModeSetuid | os.ModeSetgid | 0o755 },
+	}))
+	restored, err := cache3.Restore(context.Background(), "abc", dir3)
+	require.NoError(t, err)
+	assert.True(t, restored)
+	info, err := os.Stat(filepath.Join(dir3, "a.txt"))
+	require.NoError(t, err)
+	assert.Zero(t, info.Mode()&(os.ModeSetuid|os.ModeSetgid))
+}
+
+func TestFileServerRejectsUnsafeManifests(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(artifact.NewFileServer(t.TempDir()))
+	defer server.Close()
+
+	tests := []struct {
+		manifest string
+		want     int
+	}{
+		{manifest: `{"files":[{"path":"a.txt","hash":"0123456789abcdef0123456789abcdef"}]}`, want: http.StatusCreated},
+		{manifest: `{"files":[{"path":"../../.ssh/authorized_keys","hash":"0123456789abcdef0123456789abcdef"}]}`, want: http.StatusBadRequest},
+		{manifest: `{"files":[{"path":"/etc/passwd","hash":"0123456789abcdef0123456789abcdef"}]}`, want: http.StatusBadRequest},
+		{manifest: `{"files":[{"path":"a.txt","hash":"../../etc/passwd"}]}`, want: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		req, err := http.NewRequest(http.MethodPut, server.URL+"/ac/abc", strings.NewReader(test.manifest))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, test.want, resp.StatusCode, test.manifest)
+	}
+}
+
+func TestRemoteTimeout(t *testing.T) {
+	t.Parallel()
+
+	// A server that stops responding
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+	remote := artifact.NewHTTPRemote(server.URL, nil, artifact.HTTPRemoteWithTimeout(50*time.Millisecond))
+
+	dir := t.TempDir()
+	cache := artifact.NewCache(filepath.Join(dir, ".task", "artifacts"), artifact.CacheWithRemote(remote))
+
+	restored, err := cache.Restore(context.Background(), "abc", dir)
+	assert.True(t, artifact.IsTimeout(err), err)
+	assert.False(t, restored)
+
+	writeFile(t, filepath.Join(dir, "a.txt"), "a")
+	err = cache.Store(context.Background(), "abc", dir, []string{filepath.Join(dir, "a.txt")})
+	assert.True(t, artifact.IsTimeout(err), err)
+
+	assert.False(t, artifact.IsTimeout(artifact.ErrNotFound))
+}
@@ -0,0 +1,101 @@
+package artifact
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A FileServer is an [http.Handler] that implements the protocol used by
+// [HTTPRemote] and stores manifests and blobs in a directory on disk.
+type FileServer struct {
+	dir string
+}
+
+// NewFileServer creates a new [FileServer] that stores its data in dir.
+func NewFileServer(dir string) *FileServer {
+	return &FileServer{dir: dir}
+}
+
+func (s *FileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	kind, name, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if !ok || (kind != "ac" && kind != "cas") || !validName(name) {
+		http.NotFound(w, r)
+		return
+	}
+	path := filepath.Join(s.dir, kind, name)
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		http.ServeFile(w, r, path)
+	case http.MethodPut:
+		if err := s.put(kind, name, path, r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *FileServer) put(kind, name, path string, body io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// Reject anything that would poison the cache for other clients
+	switch kind {
+	case "ac":
+		b, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return err
+		}
+		var manifest Manifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return err
+		}
+		if err := manifest.validate(); err != nil {
+			return err
+		}
+	case "cas":
+		hash, err := hashFile(tmp.Name())
+		if err != nil {
+			return err
+		}
+		if hash != name {
+			return errHashMismatch
+		}
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// validName reports whether the given key or hash is safe to use as a
+// filename. Both are always lowercase hexadecimal strings.
+func validName(name string) bool {
+	if name == "" || len(name) > 64 {
+		return false
+	}
+	for _, r := range name {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
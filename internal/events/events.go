@@ -0,0 +1,151 @@
+// Package events writes a machine-readable stream of everything the executor
+// does as newline-delimited JSON.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mvdan.cc/sh/v3/interp"
+
+	"github.com/go-task/task/v3/errors"
+)
+
+// Type is the kind of an [Event].
+type Type string
+
+const (
+	TaskStarted        Type = "task_started"
+	TaskFinished       Type = "task_finished"
+	TaskFailed         Type = "task_failed"
+	TaskUpToDate       Type = "task_up_to_date"
+	TaskRestored       Type = "task_restored"
+	TaskSkipped        Type = "task_skipped"
+	TaskDeduplicated   Type = "task_deduplicated"
+	PreconditionFailed Type = "precondition_failed"
+	DepsStarted        Type = "deps_started"
+	DepsFinished       Type = "deps_finished"
+	DepsFailed         Type = "deps_failed"
+	CmdStarted         Type = "cmd_started"
+	CmdFinished        Type = "cmd_finished"
+	CmdFailed          Type = "cmd_failed"
+)
+
+// An Event is a single line in the stream. Fields that don't apply to an event
+// type are omitted.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Type     Type      `json:"type"`
+	Task     string    `json:"task,omitempty"`
+	Index    *int      `json:"index,omitempty"`
+	Cmd      string    `json:"cmd,omitempty"`
+	Deps     []string  `json:"deps,omitempty"`
+	Duration *float64  `json:"duration_ms,omitempty"`
+	ExitCode *int      `json:"exit_code,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Message  string    `json:"message,omitempty"`
+}
+
+// A Stream writes events to an [io.Writer] and passes them to its subscribers.
+// It is safe for concurrent use. All methods are no-ops on a nil Stream, so
+// callers don't need to check whether events are enabled.
+type Stream struct {
+	mutex       sync.Mutex
+	encoder     *json.Encoder
+	subscribers []func(Event)
+}
+
+// NewStream creates a new [Stream] that writes to w. If w is nil, events are
+// only passed to the subscribers of the stream.
+func NewStream(w io.Writer) *Stream {
+	s := &Stream{}
+	if w != nil {
+		s.encoder = json.NewEncoder(w)
+	}
+	return s
+}
+
+// Subscribe calls fn with every event emitted after it is called. Events are
+// passed to subscribers one at a time, in the order they are emitted.
+func (s *Stream) Subscribe(fn func(Event)) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.subscribers = append(s.subscribers, fn)
+}
+
+// Emit writes the given event to the stream. If the event has no time, the
+// current time is used.
+func (s *Stream) Emit(ev Event) {
+	if s == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.encoder != nil {
+		// Events are best-effort and must never fail a run
+		_ = s.encoder.Encode(ev)
+	}
+	for _, fn := range s.subscribers {
+		fn(ev)
+	}
+}
+
+// EmitResult writes the given event with the time elapsed since start. If err
+// is not nil, the event type is replaced with failed and the error and exit
+// code are added.
+func (s *Stream) EmitResult(ev Event, start time.Time, err error, failed Type) {
+	if s == nil {
+		return
+	}
+	ms := float64(time.Since(start).Microseconds()) / 1000
+	ev.Duration = &ms
+	if err != nil {
+		ev.Type = failed
+		ev.Error = err.Error()
+		if code, ok := exitCode(err); ok {
+			ev.ExitCode = &code
+		}
+	}
+	s.Emit(ev)
+}
+
+func exitCode(err error) (int, bool) {
+	var runErr *errors.TaskRunError
+	if errors.As(err, &runErr) {
+		err = runErr.Err
+	}
+	if code, ok := interp.IsExitStatus(err); ok {
+		return int(code), true
+	}
+	return 0, false
+}
+
+// Open opens the destination of an event stream. The target is either a file
+// path, which is created or truncated, or "fd:N" to write to an already open
+// file descriptor inherited from the parent process.
+func Open(target string) (io.WriteCloser, error) {
+	if fd, ok := strings.CutPrefix(target, "fd:"); ok {
+		n, err := strconv.Atoi(fd)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("task: invalid file descriptor %q", fd)
+		}
+		f := os.NewFile(uintptr(n), target)
+		if f == nil {
+			return nil, fmt.Errorf("task: invalid file descriptor %q", fd)
+		}
+		return f, nil
+	}
+	return os.Create(target)
+}
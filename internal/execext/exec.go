@@ -1,10 +1,12 @@
 package execext
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -27,6 +29,9 @@ type RunCommandOptions struct {
 	Stdin     io.Reader
 	Stdout    io.Writer
 	Stderr    io.Writer
+	// KillTimeout is how long commands are given to stop after being
+	// signaled before they are killed. Defaults to [DefaultKillTimeout].
+	KillTimeout time.Duration
 }
 
 // ErrNilOptions is returned when a nil options is given
@@ -60,7 +65,7 @@ func RunCommand(ctx context.Context, opts *RunCommandOptions) error {
 	r, err := interp.New(
 		interp.Params(params...),
 		interp.Env(expand.ListEnviron(environ...)),
-		interp.ExecHandlers(execHandler),
+		interp.ExecHandlers(execHandler(cmp.Or(opts.KillTimeout, DefaultKillTimeout))),
 		interp.OpenHandler(openHandler),
 		interp.StdIO(opts.Stdin, opts.Stdout, opts.Stderr),
 		dirOption(opts.Dir),
@@ -109,8 +114,113 @@ func Expand(s string) (string, error) {
 	return "", nil
 }
 
-func execHandler(next interp.ExecHandlerFunc) interp.ExecHandlerFunc {
-	return interp.DefaultExecHandler(15 * time.Second)
+// DefaultKillTimeout is how long a command is given to stop after being
+// signaled before it is killed, if no other timeout is given.
+const DefaultKillTimeout = 15 * time.Second
+
+func execHandler(killTimeout time.Duration) func(next interp.ExecHandlerFunc) interp.ExecHandlerFunc {
+	return func(next interp.ExecHandlerFunc) interp.ExecHandlerFunc {
+		return func(ctx context.Context, args []string) error {
+			return runProcess(ctx, args, killTimeout)
+		}
+	}
+}
+
+// runProcess works like [interp.DefaultExecHandler], except that each command
+// runs in its own process group, so that the processes it starts are stopped
+// along with it.
+//
+// When the context is cancelled, the process group is sent the signal that
+// caused it, if it was cancelled by a [errors.TaskCancelledBySignalError], or
+// SIGINT otherwise, and then killed if it doesn't stop before the kill timeout.
+// When the deadline of the context is exceeded, the process group is killed
+// right away, since a command that timed out is likely to be stuck.
+func runProcess(ctx context.Context, args []string, killTimeout time.Duration) error {
+	hc := interp.HandlerCtx(ctx)
+	path, err := interp.LookPathDir(hc.Dir, hc.Env, args[0])
+	if err != nil {
+		fmt.Fprintln(hc.Stderr, err)
+		return interp.NewExitStatus(127)
+	}
+	cmd := &exec.Cmd{
+		Path:   path,
+		Args:   args,
+		Env:    execEnv(hc.Env),
+		Dir:    hc.Dir,
+		Stdin:  hc.Stdin,
+		Stdout: hc.Stdout,
+		Stderr: hc.Stderr,
+	}
+	group := setProcessGroup(cmd, hc.Stdin)
+
+	err = cmd.Start()
+	if err == nil {
+		done := make(chan struct{})
+		stop := context.AfterFunc(ctx, func() {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				_ = killProcess(cmd, group)
+				return
+			}
+			var sig os.Signal = os.Interrupt
+			var signalErr *errors.TaskCancelledBySignalError
+			if errors.As(context.Cause(ctx), &signalErr) {
+				sig = signalErr.Signal
+			}
+			// A process that shares the process group of Task has already
+			// been sent the interrupt by the terminal
+			if group || signalErr == nil || sig != os.Interrupt {
+				_ = signalProcess(cmd, group, sig)
+			}
+			timer := time.NewTimer(killTimeout)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				_ = killProcess(cmd, group)
+			case <-done:
+			}
+		})
+
+		err = cmd.Wait()
+		close(done)
+		stop()
+	}
+
+	switch err := err.(type) {
+	case *exec.ExitError:
+		if sig, ok := signaled(err); ok {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ctx.Err()
+			}
+			return interp.NewExitStatus(uint8(128 + sig))
+		}
+		return interp.NewExitStatus(uint8(err.ExitCode()))
+	case *exec.Error:
+		// did not start
+		fmt.Fprintf(hc.Stderr, "%v\n", err)
+		return interp.NewExitStatus(127)
+	default:
+		return err
+	}
+}
+
+// execEnv returns the exported variables of the given environment in the form
+// expected by [exec.Cmd].
+func execEnv(env expand.Environ) []string {
+	list := make([]string, 0, 64)
+	for name, vr := range env.Each {
+		if !vr.IsSet() {
+			// Variables that are unset in the runner must not be inherited
+			for i, kv := range list {
+				if strings.HasPrefix(kv, name+"=") {
+					list[i] = ""
+				}
+			}
+		}
+		if vr.Exported && vr.Kind == expand.String {
+			list = append(list, name+"="+vr.String())
+		}
+	}
+	return list
 }
 
 func openHandler(ctx context.Context, path string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
@@ -0,0 +1,52 @@
+//go:build !windows
+
+package execext
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// setProcessGroup makes the command start in a new process group, so that it
+// can be stopped together with any process it starts, and returns whether it
+// did. Commands that read from a terminal are left in the foreground process
+// group of Task, since reading from a terminal in any other group stops the
+// process. The terminal sends them the signals typed by the user instead.
+func setProcessGroup(cmd *exec.Cmd, stdin io.Reader) bool {
+	if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return false
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return true
+}
+
+// signalProcess sends the given signal to the process group of the command, or
+// only to its process if it isn't in its own group.
+func signalProcess(cmd *exec.Cmd, group bool, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return cmd.Process.Signal(sig)
+	}
+	if group {
+		return syscall.Kill(-cmd.Process.Pid, s)
+	}
+	return syscall.Kill(cmd.Process.Pid, s)
+}
+
+// killProcess stops the process group of the command, or only its process if
+// it isn't in its own group, immediately.
+func killProcess(cmd *exec.Cmd, group bool) error {
+	return signalProcess(cmd, group, syscall.SIGKILL)
+}
+
+// signaled returns the signal that stopped the process, if any.
+func signaled(err *exec.ExitError) (int, bool) {
+	if status, ok := err.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return int(status.Signal()), true
+	}
+	return 0, false
+}
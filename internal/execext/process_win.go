@@ -0,0 +1,28 @@
+//go:build windows
+
+package execext
+
+import (
+	"io"
+	"os"
+	"os/exec"
+)
+
+// NOTE: Windows has no process groups that can be signaled like on Unix and
+// can't send signals other than kill, so the process itself is killed.
+
+func setProcessGroup(cmd *exec.Cmd, stdin io.Reader) bool {
+	return false
+}
+
+func signalProcess(cmd *exec.Cmd, group bool, sig os.Signal) error {
+	return cmd.Process.Kill()
+}
+
+func killProcess(cmd *exec.Cmd, group bool) error {
+	return cmd.Process.Kill()
+}
+
+func signaled(err *exec.ExitError) (int, bool) {
+	return 0, false
+}
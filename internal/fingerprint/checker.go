@@ -18,3 +18,9 @@ type SourcesCheckable interface {
 	OnError(t *ast.Task) error
 	Kind() string
 }
+
+// A SuccessRecorder is a [SourcesCheckable] that records the state of a task
+// after it runs successfully.
+type SuccessRecorder interface {
+	OnSuccess(t *ast.Task) error
+}
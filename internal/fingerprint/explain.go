@@ -0,0 +1,130 @@
+package fingerprint
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// An Explanation tells why a task is or isn't up to date.
+type Explanation struct {
+	UpToDate bool
+	// Reasons are the results of the checks that decided whether the task is
+	// up to date, in the order they were made.
+	Reasons []string
+}
+
+// A StatusExplainer is a [StatusCheckable] that can tell why the status of a
+// task is or isn't up to date.
+type StatusExplainer interface {
+	Explain(ctx context.Context, t *ast.Task) (bool, []string, error)
+}
+
+// A SourcesExplainer is a [SourcesCheckable] that can tell why the sources of
+// a task are or aren't up to date. Unlike IsUpToDate, Explain never updates
+// the fingerprints stored for the task.
+type SourcesExplainer interface {
+	Explain(t *ast.Task) (bool, []string, error)
+}
+
+// ExplainTask makes the same checks as [IsTaskUpToDate] and returns why the
+// task is or isn't up to date. It never updates the fingerprints stored for
+// the task, as if [WithDry] was given.
+func ExplainTask(
+	ctx context.Context,
+	t *ast.Task,
+	opts ...CheckerOption,
+) (*Explanation, error) {
+	config, err := newCheckerConfig(append(opts, WithDry(true))...)
+	if err != nil {
+		return nil, err
+	}
+
+	statusIsSet := len(t.Status) != 0
+	sourcesIsSet := len(t.Sources) != 0
+
+	explanation := &Explanation{}
+	if !statusIsSet && !sourcesIsSet {
+		explanation.Reasons = append(explanation.Reasons, "the task has no status or sources, so it always runs")
+		return explanation, nil
+	}
+
+	explanation.UpToDate = true
+	if statusIsSet {
+		upToDate, reasons, err := explainStatus(ctx, config.statusChecker, t)
+		if err != nil {
+			return nil, err
+		}
+		explanation.UpToDate = explanation.UpToDate && upToDate
+		explanation.Reasons = append(explanation.Reasons, reasons...)
+	}
+	if sourcesIsSet {
+		upToDate, reasons, err := explainSources(config.sourcesChecker, t)
+		if err != nil {
+			return nil, err
+		}
+		explanation.UpToDate = explanation.UpToDate && upToDate
+		explanation.Reasons = append(explanation.Reasons, reasons...)
+	}
+	return explanation, nil
+}
+
+func explainStatus(ctx context.Context, checker StatusCheckable, t *ast.Task) (bool, []string, error) {
+	if explainer, ok := checker.(StatusExplainer); ok {
+		return explainer.Explain(ctx, t)
+	}
+	upToDate, err := checker.IsUpToDate(ctx, t)
+	if err != nil {
+		return false, nil, err
+	}
+	if upToDate {
+		return true, []string{"the status of the task is up to date"}, nil
+	}
+	return false, []string{"the status of the task is not up to date"}, nil
+}
+
+func explainSources(checker SourcesCheckable, t *ast.Task) (bool, []string, error) {
+	if explainer, ok := checker.(SourcesExplainer); ok {
+		return explainer.Explain(t)
+	}
+	upToDate, err := checker.IsUpToDate(t)
+	if err != nil {
+		return false, nil, err
+	}
+	if upToDate {
+		return true, []string{fmt.Sprintf("the sources of the task are up to date (method %s)", checker.Kind())}, nil
+	}
+	return false, []string{fmt.Sprintf("the sources of the task are not up to date (method %s)", checker.Kind())}, nil
+}
+
+// missingGenerates returns the globs in the generates of the given task that
+// match no files.
+func missingGenerates(t *ast.Task) ([]string, error) {
+	var missing []string
+	for _, g := range t.Generates {
+		if g.Negate {
+			continue
+		}
+		generates, err := glob(t.Dir, g.Glob)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if len(generates) == 0 {
+			missing = append(missing, g.Glob)
+		}
+	}
+	return missing, nil
+}
+
+// relPath returns the given file relative to the directory of the given task,
+// which is how source files are stored in manifests and shown in explanations.
+func relPath(t *ast.Task, path string) string {
+	rel, err := filepath.Rel(t.Dir, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
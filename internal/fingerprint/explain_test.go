@@ -0,0 +1,148 @@
+package fingerprint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+func TestExplainTask(t *testing.T) {
+	t.Parallel()
+
+	writeFile := func(t *testing.T, path, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	t.Run("checksum", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		task := &ast.Task{
+			Task:      "build",
+			Dir:       dir,
+			Sources:   []*ast.Glob{{Glob: "src/**/*.go"}},
+			Generates: []*ast.Glob{{Glob: "app"}},
+		}
+		writeFile(t, filepath.Join(dir, "src/main.go"), "main")
+		writeFile(t, filepath.Join(dir, "src/lib/a.go"), "a")
+		writeFile(t, filepath.Join(dir, "src/lib/b.go"), "b")
+		opts := []CheckerOption{WithMethod("checksum"), WithTempDir(filepath.Join(dir, ".task"))}
+
+		explanation, err := ExplainTask(context.Background(), task, opts...)
+		require.NoError(t, err)
+		assert.Equal(t, &Explanation{
+			UpToDate: false,
+			Reasons: []string{
+				"no checksum of the sources was stored, so the task has never run",
+				`generates "app" matched no files`,
+			},
+		}, explanation)
+
+		// The task runs
+		upToDate, err := IsTaskUpToDate(context.Background(), task, opts...)
+		require.NoError(t, err)
+		require.False(t, upToDate)
+		writeFile(t, filepath.Join(dir, "app"), "app")
+
+		explanation, err = ExplainTask(context.Background(), task, opts...)
+		require.NoError(t, err)
+		assert.Equal(t, &Explanation{
+			UpToDate: true,
+			Reasons:  []string{"none of the 3 source files changed since the last run"},
+		}, explanation)
+
+		writeFile(t, filepath.Join(dir, "src/main.go"), "changed")
+		writeFile(t, filepath.Join(dir, "src/lib/c.go"), "c")
+		require.NoError(t, os.Remove(filepath.Join(dir, "src/lib/a.go")))
+
+		explanation, err = ExplainTask(context.Background(), task, opts...)
+		require.NoError(t, err)
+		assert.Equal(t, &Explanation{
+			UpToDate: false,
+			Reasons: []string{
+				"source file src/lib/c.go was added",
+				"source file src/main.go changed",
+				"source file src/lib/a.go was removed",
+			},
+		}, explanation)
+
+		// Explaining doesn't update the stored checksums
+		upToDate, err = IsTaskUpToDate(context.Background(), task, opts...)
+		require.NoError(t, err)
+		assert.False(t, upToDate)
+	})
+
+	t.Run("timestamp", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		task := &ast.Task{
+			Task:      "build",
+			Dir:       dir,
+			Sources:   []*ast.Glob{{Glob: "*.go"}},
+			Generates: []*ast.Glob{{Glob: "app"}},
+		}
+		past := time.Now().Add(-time.Hour)
+		for _, name := range []string{"a.go", "b.go", "app"} {
+			writeFile(t, filepath.Join(dir, name), name)
+			require.NoError(t, os.Chtimes(filepath.Join(dir, name), past, past))
+		}
+		opts := []CheckerOption{WithMethod("timestamp"), WithTempDir(filepath.Join(dir, ".task"))}
+
+		explanation, err := ExplainTask(context.Background(), task, opts...)
+		require.NoError(t, err)
+		assert.Equal(t, &Explanation{
+			UpToDate: true,
+			Reasons:  []string{"none of the 2 source files is newer than generated file app"},
+		}, explanation)
+
+		require.NoError(t, os.Chtimes(filepath.Join(dir, "b.go"), time.Now(), time.Now()))
+
+		explanation, err = ExplainTask(context.Background(), task, opts...)
+		require.NoError(t, err)
+		assert.Equal(t, &Explanation{
+			UpToDate: false,
+			Reasons:  []string{"source file b.go is newer than generated file app"},
+		}, explanation)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		t.Parallel()
+
+		task := &ast.Task{
+			Task:   "build",
+			Dir:    t.TempDir(),
+			Status: []string{"true", "exit 1", "true"},
+		}
+
+		explanation, err := ExplainTask(context.Background(), task)
+		require.NoError(t, err)
+		assert.Equal(t, &Explanation{
+			UpToDate: false,
+			Reasons: []string{
+				`status command "true" succeeded`,
+				`status command "exit 1" failed: exit status 1`,
+			},
+		}, explanation)
+	})
+
+	t.Run("always runs", func(t *testing.T) {
+		t.Parallel()
+
+		explanation, err := ExplainTask(context.Background(), &ast.Task{Task: "build"})
+		require.NoError(t, err)
+		assert.Equal(t, &Explanation{
+			UpToDate: false,
+			Reasons:  []string{"the task has no status or sources, so it always runs"},
+		}, explanation)
+	})
+}
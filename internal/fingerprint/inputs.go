@@ -0,0 +1,103 @@
+package fingerprint
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/zeebo/xxh3"
+
+	"github.com/go-task/task/v3/internal/experiments"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// inputs returns the hashes of the inputs of the given task, besides its
+// sources, that are part of its fingerprint, by the name of each input. Only
+// hashes are returned, so the values of variables aren't stored on disk. It
+// returns nil if the task has no fingerprint.
+func inputs(t *ast.Task) map[string]string {
+	if t.Fingerprint == nil {
+		return nil
+	}
+
+	var cmds strings.Builder
+	for _, cmd := range t.Cmds {
+		fmt.Fprintf(&cmds, "%s\x00%s\x00", cmd.Cmd, cmd.Task)
+		for k, v := range cmd.Vars.All() {
+			fmt.Fprintf(&cmds, "%s=%v\x00", k, v.Value)
+		}
+		cmds.WriteByte('\n')
+	}
+	m := map[string]string{"cmds": hashString(cmds.String())}
+	if t.Location != nil {
+		m["taskfile"] = hashString(t.Location.Taskfile)
+	}
+	for _, name := range t.Fingerprint.Vars {
+		m["var:"+name] = ""
+		if v, ok := t.Vars.Get(name); ok {
+			m["var:"+name] = hashString(fmt.Sprint(v.Value))
+		}
+	}
+	for _, name := range t.Fingerprint.Env {
+		m["env:"+name] = ""
+		if value, ok := envValue(t, name); ok {
+			m["env:"+name] = hashString(value)
+		}
+	}
+	return m
+}
+
+// envValue returns the value the given environment variable has when the
+// commands of the given task run.
+func envValue(t *ast.Task, name string) (string, bool) {
+	if v, ok := t.Env.Get(name); ok {
+		if _, alreadySet := os.LookupEnv(name); !alreadySet || experiments.EnvPrecedence.Enabled() {
+			return fmt.Sprint(v.Value), true
+		}
+	}
+	return os.LookupEnv(name)
+}
+
+func hashString(s string) string {
+	hash := xxh3.HashString128(s)
+	return fmt.Sprintf("%x%x", hash.Hi, hash.Lo)
+}
+
+// diffInputs tells which inputs changed between the old manifest and the
+// current one.
+func (old *manifest) diffInputs(current *manifest) []string {
+	var reasons []string
+	names := make(map[string]string, len(current.Inputs))
+	maps.Copy(names, old.Inputs)
+	maps.Copy(names, current.Inputs)
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		oldHash, wasFingerprinted := old.Inputs[name]
+		hash, isFingerprinted := current.Inputs[name]
+		switch {
+		case !wasFingerprinted && isFingerprinted:
+			reasons = append(reasons, fmt.Sprintf("%s wasn't fingerprinted in the last run", describeInput(name)))
+		case wasFingerprinted && !isFingerprinted:
+			reasons = append(reasons, fmt.Sprintf("%s is no longer fingerprinted", describeInput(name)))
+		case oldHash != hash:
+			reasons = append(reasons, fmt.Sprintf("%s changed since the last run", describeInput(name)))
+		}
+	}
+	return reasons
+}
+
+func describeInput(name string) string {
+	kind, name, _ := strings.Cut(name, ":")
+	switch kind {
+	case "cmds":
+		return "the commands of the task"
+	case "taskfile":
+		return "the location of the Taskfile"
+	case "var":
+		return fmt.Sprintf("variable %s", name)
+	case "env":
+		return fmt.Sprintf("environment variable %s", name)
+	}
+	return kind
+}
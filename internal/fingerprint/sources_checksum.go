@@ -1,30 +1,42 @@
 package fingerprint
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"os"
-	"path/filepath"
-	"regexp"
-	"strings"
+	"slices"
+	"time"
 
 	"github.com/zeebo/xxh3"
 
-	"github.com/go-task/task/v3/internal/filepathext"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
 
 // ChecksumChecker validates if a task is up to date by calculating its source
 // files checksum
 type ChecksumChecker struct {
-	tempDir string
-	dry     bool
+	store *Store
+	dry   bool
+	// kind is the name of the method
+	kind string
+	// hashFiles computes the manifest entries of files
+	hashFiles fileHasher
 }
 
+// A fileHasher computes the manifest entries of the given files, by their path
+// relative to the directory of the given task. Entries in old that are still
+// current can be reused, if written is when they were stored.
+type fileHasher func(t *ast.Task, paths []string, old map[string]manifestFile, written time.Time) (map[string]manifestFile, error)
+
 func NewChecksumChecker(tempDir string, dry bool) *ChecksumChecker {
 	return &ChecksumChecker{
-		tempDir: tempDir,
-		dry:     dry,
+		store:     NewStore(tempDir),
+		dry:       dry,
+		kind:      "checksum",
+		hashFiles: hashFiles,
 	}
 }
 
@@ -33,94 +45,321 @@ func (checker *ChecksumChecker) IsUpToDate(t *ast.Task) (bool, error) {
 		return false, nil
 	}
 
-	checksumFile := checker.checksumFilePath(t)
-
-	data, _ := os.ReadFile(checksumFile)
-	oldHash := strings.TrimSpace(string(data))
+	old := checker.readManifest(t)
 
-	newHash, err := checker.checksum(t)
+	current, err := checker.manifest(t, old)
 	if err != nil {
 		return false, nil
 	}
+	current.Inputs = inputs(t)
+	// The generated files are only recorded after the task runs successfully
+	current.Generates = old.Generates
 
-	if !checker.dry && oldHash != newHash {
-		_ = os.MkdirAll(filepathext.SmartJoin(checker.tempDir, "checksum"), 0o755)
-		if err = os.WriteFile(checksumFile, []byte(newHash+"\n"), 0o644); err != nil {
+	if !checker.dry && !current.equal(old) {
+		err := checker.store.Update(t, checker.kind, func(e *Entry) bool {
+			e.Manifest = current
+			return true
+		})
+		if err != nil {
 			return false, err
 		}
 	}
 
-	if len(t.Generates) > 0 {
-		// For each specified 'generates' field, check whether the files actually exist
-		for _, g := range t.Generates {
-			if g.Negate {
-				continue
-			}
-			generates, err := glob(t.Dir, g.Glob)
-			if os.IsNotExist(err) {
-				return false, nil
-			}
-			if err != nil {
-				return false, err
-			}
-			if len(generates) == 0 {
-				return false, nil
-			}
+	// For each specified 'generates' field, check whether the files actually exist
+	missing, err := missingGenerates(t)
+	if err != nil {
+		return false, err
+	}
+	if len(missing) > 0 {
+		return false, nil
+	}
+
+	if old.Checksum != current.Checksum || !maps.Equal(old.Inputs, current.Inputs) {
+		return false, nil
+	}
+	return checker.generatesAreUpToDate(t, old)
+}
+
+// Explain tells which source files and fingerprinted inputs changed since the
+// manifest of the sources of the given task was stored, and which of its
+// generates match no files or were changed.
+func (checker *ChecksumChecker) Explain(t *ast.Task) (bool, []string, error) {
+	old := checker.readManifest(t)
+
+	current, err := checker.manifest(t, old)
+	if err != nil {
+		return false, nil, err
+	}
+	current.Inputs = inputs(t)
+
+	var reasons []string
+	switch {
+	case old.Checksum == "":
+		reasons = append(reasons, "no checksum of the sources was stored, so the task has never run")
+	case old.Checksum == current.Checksum:
+		reasons = append(reasons, fmt.Sprintf("none of the %d source files changed since the last run", len(current.Files)))
+	default:
+		reasons = append(reasons, old.diff(current)...)
+	}
+	upToDate := old.Checksum != "" && old.Checksum == current.Checksum
+
+	if old.Checksum != "" {
+		inputReasons := old.diffInputs(current)
+		reasons = append(reasons, inputReasons...)
+		upToDate = upToDate && len(inputReasons) == 0
+	}
+
+	missing, err := missingGenerates(t)
+	if err != nil {
+		return false, nil, err
+	}
+	for _, g := range missing {
+		reasons = append(reasons, fmt.Sprintf("generates %q matched no files", g))
+	}
+	upToDate = upToDate && len(missing) == 0
+
+	if upToDate && t.Fingerprint != nil && t.Fingerprint.Generates {
+		generates, err := checker.generates(t, old)
+		if err != nil {
+			return false, nil, err
 		}
+		generatesReasons := diffFiles("generated file", old.Generates, generates)
+		reasons = append(reasons, generatesReasons...)
+		upToDate = len(generatesReasons) == 0
 	}
 
-	return oldHash == newHash, nil
+	return upToDate, reasons, nil
 }
 
 func (checker *ChecksumChecker) Value(t *ast.Task) (any, error) {
-	return checker.checksum(t)
+	old := checker.readManifest(t)
+	m, err := checker.manifest(t, old)
+	if err != nil {
+		return "", err
+	}
+	return m.Checksum, nil
 }
 
 func (checker *ChecksumChecker) OnError(t *ast.Task) error {
 	if len(t.Sources) == 0 {
 		return nil
 	}
-	return os.Remove(checker.checksumFilePath(t))
+	return checker.store.Delete(t)
 }
 
-func (*ChecksumChecker) Kind() string {
-	return "checksum"
+// OnSuccess records the contents of the generated files of the given task in
+// its manifest, if they are part of the fingerprint of the task, so the task
+// runs again if they are changed.
+func (checker *ChecksumChecker) OnSuccess(t *ast.Task) error {
+	if len(t.Sources) == 0 || t.Fingerprint == nil || !t.Fingerprint.Generates || checker.dry {
+		return nil
+	}
+
+	m := checker.readManifest(t)
+	if m.Checksum == "" {
+		return nil
+	}
+	generates, err := checker.generates(t, m)
+	if err != nil {
+		return err
+	}
+	return checker.store.Update(t, checker.kind, func(e *Entry) bool {
+		if e.Manifest == nil {
+			return false
+		}
+		e.Manifest.Generates = generates
+		return true
+	})
+}
+
+func (checker *ChecksumChecker) Kind() string {
+	return checker.kind
+}
+
+type (
+	// A manifest lists the source files of a task by their path relative to
+	// the directory of the task, together with the checksum of all of them,
+	// which decides whether the task is up to date.
+	manifest struct {
+		Checksum string                  `json:"checksum"`
+		Files    map[string]manifestFile `json:"files"`
+		// Inputs are the hashes of the inputs of the task besides its
+		// sources, if the task has a fingerprint
+		Inputs map[string]string `json:"inputs,omitempty"`
+		// Generates are the generated files of the task when it last ran
+		// successfully, if they are part of the fingerprint of the task
+		Generates map[string]manifestFile `json:"generates,omitempty"`
+		// written is when the manifest was stored, if it was read from disk
+		written time.Time
+	}
+	// A manifestFile is the checksum of a source file and the size and
+	// modification time the file had when it was computed.
+	manifestFile struct {
+		Hash    string `json:"hash"`
+		Size    int64  `json:"size"`
+		ModTime int64  `json:"mtime"`
+	}
+)
+
+// readManifest reads the manifest stored for the given task. It returns an
+// empty manifest if none was stored.
+func (checker *ChecksumChecker) readManifest(t *ast.Task) *manifest {
+	e, err := checker.store.Get(t, checker.kind)
+	if err != nil || e == nil || e.Manifest == nil {
+		return &manifest{}
+	}
+	m := e.Manifest
+	m.written = e.Updated
+	return m
 }
 
-func (c *ChecksumChecker) checksum(t *ast.Task) (string, error) {
+// manifest computes the manifest of the sources of the given task. Files whose
+// size and modification time didn't change since the old manifest was stored
+// aren't read again.
+func (checker *ChecksumChecker) manifest(t *ast.Task, old *manifest) (*manifest, error) {
 	sources, err := Globs(t.Dir, t.Sources)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	files, err := checker.hashFiles(t, sources, old.Files, old.written)
+	if err != nil {
+		return nil, err
 	}
 
+	m := &manifest{Files: files}
 	h := xxh3.New()
-	buf := make([]byte, 128*1024)
 	for _, f := range sources {
-		// also sum the filename, so checksum changes for renaming a file
-		if _, err := io.CopyBuffer(h, strings.NewReader(filepath.Base(f)), buf); err != nil {
-			return "", err
-		}
-		f, err := os.Open(f)
+		// also sum the path, so checksum changes for renaming or moving a file
+		path := relPath(t, f)
+		fmt.Fprintf(h, "%s\x00%s\n", path, files[path].Hash)
+	}
+
+	hash := h.Sum128()
+	m.Checksum = fmt.Sprintf("%x%x", hash.Hi, hash.Lo)
+	return m, nil
+}
+
+// generates computes the manifest entries of the generated files of the given
+// task, reusing the ones in the given manifest that are still current.
+func (checker *ChecksumChecker) generates(t *ast.Task, m *manifest) (map[string]manifestFile, error) {
+	generates, err := Globs(t.Dir, t.Generates)
+	if err != nil {
+		return nil, err
+	}
+	return checker.hashFiles(t, generates, m.Generates, m.written)
+}
+
+// generatesAreUpToDate tells whether the generated files of the given task are
+// the same as the ones recorded in its manifest, if they are part of the
+// fingerprint of the task.
+func (checker *ChecksumChecker) generatesAreUpToDate(t *ast.Task, m *manifest) (bool, error) {
+	if t.Fingerprint == nil || !t.Fingerprint.Generates {
+		return true, nil
+	}
+	generates, err := checker.generates(t, m)
+	if err != nil {
+		return false, err
+	}
+	return len(diffFiles("generated file", m.Generates, generates)) == 0, nil
+}
+
+// hashFiles is the [fileHasher] of the checksum method, which hashes the
+// contents of the given files.
+func hashFiles(t *ast.Task, paths []string, old map[string]manifestFile, written time.Time) (map[string]manifestFile, error) {
+	buf := make([]byte, 128*1024)
+	return hashFilesWith(t, paths, old, written, func(path string, _ os.FileInfo) (string, error) {
+		return hashFile(path, buf)
+	})
+}
+
+// hashFilesWith computes the manifest entries of the given files with the
+// given hash function. Files whose size and modification time didn't change
+// since the old entries were stored aren't hashed again.
+func hashFilesWith(
+	t *ast.Task,
+	paths []string,
+	old map[string]manifestFile,
+	written time.Time,
+	hash func(path string, info os.FileInfo) (string, error),
+) (map[string]manifestFile, error) {
+	files := make(map[string]manifestFile, len(paths))
+	for _, f := range paths {
+		info, err := os.Stat(f)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		if _, err = io.CopyBuffer(h, f, buf); err != nil {
-			return "", err
+		path := relPath(t, f)
+		file, ok := old[path]
+		// A file that was modified while the old entries were being stored
+		// can be modified again without changing its modification time
+		if !ok || file.Size != info.Size() || file.ModTime != info.ModTime().UnixNano() || !info.ModTime().Before(written) {
+			hash, err := hash(f, info)
+			if err != nil {
+				return nil, err
+			}
+			file = manifestFile{Hash: hash, Size: info.Size(), ModTime: info.ModTime().UnixNano()}
 		}
-		f.Close()
+		files[path] = file
 	}
+	return files, nil
+}
 
+func hashFile(path string, buf []byte) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := xxh3.New()
+	if _, err := io.CopyBuffer(h, f, buf); err != nil {
+		return "", err
+	}
 	hash := h.Sum128()
 	return fmt.Sprintf("%x%x", hash.Hi, hash.Lo), nil
 }
 
-func (checker *ChecksumChecker) checksumFilePath(t *ast.Task) string {
-	return filepath.Join(checker.tempDir, "checksum", normalizeFilename(t.Name()))
+// equal tells whether the manifest would be stored the same as the other.
+func (m *manifest) equal(other *manifest) bool {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return false
+	}
+	otherData, err := json.Marshal(other)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(data, otherData)
 }
 
-var checksumFilenameRegexp = regexp.MustCompile("[^A-z0-9]")
+// diff tells which source files were added, removed or changed between the
+// old manifest and the current one.
+func (old *manifest) diff(current *manifest) []string {
+	reasons := diffFiles("source file", old.Files, current.Files)
+	if len(reasons) == 0 {
+		// The manifest can't always tell what changed
+		reasons = append(reasons, "the checksum of the source files changed since the last run")
+	}
+	return reasons
+}
 
-// replaces invalid characters on filenames with "-"
-func normalizeFilename(f string) string {
-	return checksumFilenameRegexp.ReplaceAllString(f, "-")
+// diffFiles tells which files were added, removed or changed between the old
+// manifest entries and the current ones.
+func diffFiles(kind string, old, current map[string]manifestFile) []string {
+	var reasons []string
+	for _, path := range slices.Sorted(maps.Keys(current)) {
+		oldFile, ok := old[path]
+		switch {
+		case !ok:
+			reasons = append(reasons, fmt.Sprintf("%s %s was added", kind, path))
+		case oldFile.Hash != current[path].Hash:
+			reasons = append(reasons, fmt.Sprintf("%s %s changed", kind, path))
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(old)) {
+		if _, ok := current[path]; !ok {
+			reasons = append(reasons, fmt.Sprintf("%s %s was removed", kind, path))
+		}
+	}
+	return reasons
 }
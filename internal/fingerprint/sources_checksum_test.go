@@ -1,23 +1,127 @@
 package fingerprint
 
 import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/taskfile/ast"
 )
 
-func TestNormalizeFilename(t *testing.T) {
+func TestChecksumManifest(t *testing.T) {
 	t.Parallel()
 
-	tests := []struct {
-		In, Out string
-	}{
-		{"foobarbaz", "foobarbaz"},
-		{"foo/bar/baz", "foo-bar-baz"},
-		{"foo@bar/baz", "foo-bar-baz"},
-		{"foo1bar2baz3", "foo1bar2baz3"},
+	dir := t.TempDir()
+	task := &ast.Task{
+		Task:    "build",
+		Dir:     dir,
+		Sources: []*ast.Glob{{Glob: "**/*.txt"}},
 	}
-	for _, test := range tests {
-		assert.Equal(t, test.Out, normalizeFilename(test.In))
+	writeFile := func(path, content string, modTime time.Time) {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
 	}
+	past := time.Now().Add(-time.Hour)
+	writeFile("a/file.txt", "foo", past)
+	writeFile("b/file.txt", "bar", past)
+
+	checker := NewChecksumChecker(filepath.Join(dir, ".task"), false)
+	m, err := checker.manifest(task, &manifest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/file.txt", "b/file.txt"}, slices.Sorted(maps.Keys(m.Files)))
+	assert.NotEqual(t, m.Files["a/file.txt"].Hash, m.Files["b/file.txt"].Hash)
+
+	// Files with the same name in different directories can't be swapped
+	// without changing the checksum
+	writeFile("a/file.txt", "bar", past)
+	writeFile("b/file.txt", "foo", past)
+	swapped, err := checker.manifest(task, &manifest{})
+	require.NoError(t, err)
+	assert.NotEqual(t, m.Checksum, swapped.Checksum)
+
+	// Files whose size and modification time didn't change since the manifest
+	// was stored aren't read again
+	m.written = time.Now()
+	unchanged, err := checker.manifest(task, m)
+	require.NoError(t, err)
+	assert.Equal(t, m.Checksum, unchanged.Checksum)
+
+	// Unless they were modified while the manifest was stored
+	m.written = past
+	rehashed, err := checker.manifest(task, m)
+	require.NoError(t, err)
+	assert.Equal(t, swapped.Checksum, rehashed.Checksum)
+}
+
+func TestChecksumFingerprint(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	newTask := func(cmd, version, goos string) *ast.Task {
+		return &ast.Task{
+			Task:      "build",
+			Dir:       dir,
+			Cmds:      []*ast.Cmd{{Cmd: cmd}},
+			Vars:      ast.NewVars(&ast.VarElement{Key: "VERSION", Value: ast.Var{Value: version}}),
+			Env:       ast.NewVars(&ast.VarElement{Key: "TASK_TEST_GOOS", Value: ast.Var{Value: goos}}),
+			Sources:   []*ast.Glob{{Glob: "*.go"}},
+			Generates: []*ast.Glob{{Glob: "app"}},
+			Fingerprint: &ast.Fingerprint{
+				Vars:      []string{"VERSION"},
+				Env:       []string{"TASK_TEST_GOOS"},
+				Generates: true,
+			},
+		}
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("main"), 0o644))
+	checker := NewChecksumChecker(filepath.Join(dir, ".task"), false)
+	explain := func(task *ast.Task) []string {
+		t.Helper()
+		_, reasons, err := checker.Explain(task)
+		require.NoError(t, err)
+		return reasons
+	}
+
+	// The task runs
+	task := newTask("go build", "1.0", "linux")
+	upToDate, err := checker.IsUpToDate(task)
+	require.NoError(t, err)
+	require.False(t, upToDate)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app"), []byte("app"), 0o644))
+	require.NoError(t, checker.OnSuccess(task))
+
+	upToDate, err = checker.IsUpToDate(task)
+	require.NoError(t, err)
+	assert.True(t, upToDate)
+
+	assert.Equal(t, []string{
+		"none of the 1 source files changed since the last run",
+		"the commands of the task changed since the last run",
+	}, explain(newTask("go build -v", "1.0", "linux")))
+	assert.Equal(t, []string{
+		"none of the 1 source files changed since the last run",
+		"variable VERSION changed since the last run",
+	}, explain(newTask("go build", "1.1", "linux")))
+	assert.Equal(t, []string{
+		"none of the 1 source files changed since the last run",
+		"environment variable TASK_TEST_GOOS changed since the last run",
+	}, explain(newTask("go build", "1.0", "darwin")))
+
+	// Tampering with the generated files makes the task run again
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app"), []byte("tampered"), 0o644))
+	assert.Equal(t, []string{
+		"none of the 1 source files changed since the last run",
+		"generated file app changed",
+	}, explain(task))
+	upToDate, err = checker.IsUpToDate(task)
+	require.NoError(t, err)
+	assert.False(t, upToDate)
 }
@@ -0,0 +1,129 @@
+package fingerprint
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// GitChecker validates if a task is up to date like [ChecksumChecker], but
+// uses the object IDs git already computed for the tracked source files that
+// weren't modified, instead of reading them. Modified and untracked files are
+// hashed the way git would hash them. Outside a git repository, it hashes the
+// source files like [ChecksumChecker].
+type GitChecker struct {
+	*ChecksumChecker
+}
+
+func NewGitChecker(tempDir string, dry bool) *GitChecker {
+	return &GitChecker{
+		ChecksumChecker: &ChecksumChecker{
+			store:     NewStore(tempDir),
+			dry:       dry,
+			kind:      "git",
+			hashFiles: gitHashFiles,
+		},
+	}
+}
+
+// gitHashFiles is the [fileHasher] of the git method.
+func gitHashFiles(t *ast.Task, paths []string, old map[string]manifestFile, written time.Time) (map[string]manifestFile, error) {
+	wt, err := openWorktree(t.Dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return hashFiles(t, paths, old, written)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hashFilesWith(t, paths, old, written, func(path string, info os.FileInfo) (string, error) {
+		if hash, ok := wt.objectID(path, info); ok {
+			return hash.String(), nil
+		}
+		return hashBlob(path, info.Size())
+	})
+}
+
+// A worktree is the index of a git repository, with the entries of the
+// tracked files by their absolute path.
+type worktree struct {
+	entries map[string]*index.Entry
+	// written is when the index was stored
+	written time.Time
+}
+
+func openWorktree(dir string) (*worktree, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	root, err := filepath.Abs(w.Filesystem.Root())
+	if err != nil {
+		return nil, err
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	wt := &worktree{entries: make(map[string]*index.Entry, len(idx.Entries))}
+	for _, e := range idx.Entries {
+		wt.entries[filepath.Join(root, filepath.FromSlash(e.Name))] = e
+	}
+	if storage, ok := repo.Storer.(*filesystem.Storage); ok {
+		if info, err := storage.Filesystem().Stat("index"); err == nil {
+			wt.written = info.ModTime()
+		}
+	}
+	return wt, nil
+}
+
+// objectID returns the object ID git stored for the given file, if the file is
+// tracked and wasn't modified since it was staged.
+func (wt *worktree) objectID(path string, info os.FileInfo) (plumbing.Hash, bool) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+	e, ok := wt.entries[path]
+	// Files with conflicts have entries at stages 1 to 3 instead of 0
+	if !ok || e.Stage != 0 || e.IntentToAdd {
+		return plumbing.ZeroHash, false
+	}
+	// Like git, consider files modified at the time the index was stored as
+	// modified, since they could have changed without changing their
+	// modification time
+	if e.Size != uint32(info.Size()) || !e.ModifiedAt.Equal(info.ModTime()) || !info.ModTime().Before(wt.written) {
+		return plumbing.ZeroHash, false
+	}
+	return e.Hash, true
+}
+
+// hashBlob returns the object ID the given file would have in git.
+func hashBlob(path string, size int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := plumbing.NewHasher(plumbing.BlobObject, size)
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return h.Sum().String(), nil
+}
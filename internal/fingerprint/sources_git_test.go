@@ -0,0 +1,100 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+func TestGitChecker(t *testing.T) {
+	t.Parallel()
+
+	past := time.Now().Add(-time.Hour)
+	writeFile := func(t *testing.T, path, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		require.NoError(t, os.Chtimes(path, past, past))
+	}
+	blobID := func(content string) string {
+		return plumbing.ComputeHash(plumbing.BlobObject, []byte(content)).String()
+	}
+
+	t.Run("repository", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		repo, err := git.PlainInit(dir, false)
+		require.NoError(t, err)
+		wt, err := repo.Worktree()
+		require.NoError(t, err)
+		writeFile(t, filepath.Join(dir, "tracked.txt"), "tracked")
+		_, err = wt.Add("tracked.txt")
+		require.NoError(t, err)
+		writeFile(t, filepath.Join(dir, "untracked.txt"), "untracked")
+
+		task := &ast.Task{
+			Task:    "build",
+			Dir:     dir,
+			Sources: []*ast.Glob{{Glob: "*.txt"}},
+		}
+		checker := NewGitChecker(filepath.Join(dir, ".task"), false)
+		m, err := checker.manifest(task, &manifest{})
+		require.NoError(t, err)
+		assert.Equal(t, blobID("tracked"), m.Files["tracked.txt"].Hash)
+		assert.Equal(t, blobID("untracked"), m.Files["untracked.txt"].Hash)
+
+		// Like git, tracked files whose size and modification time didn't
+		// change aren't read
+		writeFile(t, filepath.Join(dir, "tracked.txt"), "TRACKED")
+		m, err = checker.manifest(task, &manifest{})
+		require.NoError(t, err)
+		assert.Equal(t, blobID("tracked"), m.Files["tracked.txt"].Hash)
+
+		// Modified files are
+		writeFile(t, filepath.Join(dir, "tracked.txt"), "modified")
+		m, err = checker.manifest(task, &manifest{})
+		require.NoError(t, err)
+		assert.Equal(t, blobID("modified"), m.Files["tracked.txt"].Hash)
+
+		upToDate, err := checker.IsUpToDate(task)
+		require.NoError(t, err)
+		require.False(t, upToDate)
+		upToDate, err = checker.IsUpToDate(task)
+		require.NoError(t, err)
+		assert.True(t, upToDate)
+		e, err := checker.store.Get(task, "git")
+		require.NoError(t, err)
+		assert.NotNil(t, e)
+
+		writeFile(t, filepath.Join(dir, "untracked.txt"), "changed")
+		upToDate, err = checker.IsUpToDate(task)
+		require.NoError(t, err)
+		assert.False(t, upToDate)
+	})
+
+	t.Run("outside a repository", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "file.txt"), "file")
+
+		task := &ast.Task{
+			Task:    "build",
+			Dir:     dir,
+			Sources: []*ast.Glob{{Glob: "*.txt"}},
+		}
+		m, err := NewGitChecker(filepath.Join(dir, ".task"), false).manifest(task, &manifest{})
+		require.NoError(t, err)
+		expected, err := NewChecksumChecker(filepath.Join(dir, ".task"), false).manifest(task, &manifest{})
+		require.NoError(t, err)
+		assert.Equal(t, expected, m)
+	})
+}
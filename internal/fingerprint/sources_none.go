@@ -10,6 +10,10 @@ func (NoneChecker) IsUpToDate(t *ast.Task) (bool, error) {
 	return false, nil
 }
 
+func (NoneChecker) Explain(t *ast.Task) (bool, []string, error) {
+	return false, []string{`the "none" method never considers the sources up to date`}, nil
+}
+
 func (NoneChecker) Value(t *ast.Task) (any, error) {
 	return "", nil
 }
@@ -1,8 +1,8 @@
 package fingerprint
 
 import (
+	"fmt"
 	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/go-task/task/v3/taskfile/ast"
@@ -11,14 +11,14 @@ import (
 // TimestampChecker checks if any source change compared with the generated files,
 // using file modifications timestamps.
 type TimestampChecker struct {
-	tempDir string
-	dry     bool
+	store *Store
+	dry   bool
 }
 
 func NewTimestampChecker(tempDir string, dry bool) *TimestampChecker {
 	return &TimestampChecker{
-		tempDir: tempDir,
-		dry:     dry,
+		store: NewStore(tempDir),
+		dry:   dry,
 	}
 }
 
@@ -37,34 +37,32 @@ func (checker *TimestampChecker) IsUpToDate(t *ast.Task) (bool, error) {
 		return false, nil
 	}
 
-	timestampFile := checker.timestampFilePath(t)
+	taskTime := time.Now()
 
-	// If the file exists, add the file path to the generates.
+	// If the task ran before, the last run counts as a generate.
 	// If the generate file is old, the task will be executed.
-	_, err = os.Stat(timestampFile)
-	if err == nil {
-		generates = append(generates, timestampFile)
-	} else {
-		// Create the timestamp file for the next execution when the file does not exist.
-		if !checker.dry {
-			if err := os.MkdirAll(filepath.Dir(timestampFile), 0o755); err != nil {
-				return false, err
-			}
-			f, err := os.Create(timestampFile)
-			if err != nil {
-				return false, err
-			}
-			f.Close()
+	lastRun, err := checker.lastRun(t)
+	if err != nil {
+		return false, err
+	}
+	if lastRun.IsZero() && !checker.dry {
+		// Record the first run for the next execution
+		if err := checker.setLastRun(t, taskTime); err != nil {
+			return false, err
 		}
 	}
 
-	taskTime := time.Now()
-
 	// Compare the time of the generates and sources. If the generates are old, the task will be executed.
 
 	// Get the max time of the generates.
 	generateMaxTime, err := getMaxTime(generates...)
-	if err != nil || generateMaxTime.IsZero() {
+	if err != nil {
+		return false, nil
+	}
+	if lastRun.After(generateMaxTime) {
+		generateMaxTime = lastRun
+	}
+	if generateMaxTime.IsZero() {
 		return false, nil
 	}
 
@@ -74,9 +72,8 @@ func (checker *TimestampChecker) IsUpToDate(t *ast.Task) (bool, error) {
 		return false, nil
 	}
 
-	// Modify the metadata of the file to the the current time.
 	if !checker.dry {
-		if err := os.Chtimes(timestampFile, taskTime, taskTime); err != nil {
+		if err := checker.setLastRun(t, taskTime); err != nil {
 			return false, err
 		}
 	}
@@ -84,6 +81,61 @@ func (checker *TimestampChecker) IsUpToDate(t *ast.Task) (bool, error) {
 	return !shouldUpdate, nil
 }
 
+// Explain tells which source files of the given task are newer than its
+// newest generated file or than its last run.
+func (checker *TimestampChecker) Explain(t *ast.Task) (bool, []string, error) {
+	sources, err := Globs(t.Dir, t.Sources)
+	if err != nil {
+		return false, nil, err
+	}
+	generates, err := Globs(t.Dir, t.Generates)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var reasons []string
+	missing, err := missingGenerates(t)
+	if err != nil {
+		return false, nil, err
+	}
+	for _, g := range missing {
+		reasons = append(reasons, fmt.Sprintf("generates %q matched no files", g))
+	}
+
+	newest, generateMaxTime, err := newestFile(generates...)
+	if err != nil {
+		return false, nil, err
+	}
+	newestName := "generated file " + relPath(t, newest)
+	lastRun, err := checker.lastRun(t)
+	if err != nil {
+		return false, nil, err
+	}
+	if lastRun.After(generateMaxTime) {
+		generateMaxTime = lastRun
+		newestName = "the last run"
+	}
+	if generateMaxTime.IsZero() {
+		return false, append(reasons, "no generated files exist and the task has never run"), nil
+	}
+
+	upToDate := true
+	for _, f := range sources {
+		info, err := os.Stat(f)
+		if err != nil {
+			return false, nil, err
+		}
+		if info.ModTime().After(generateMaxTime) {
+			upToDate = false
+			reasons = append(reasons, fmt.Sprintf("source file %s is newer than %s", relPath(t, f), newestName))
+		}
+	}
+	if upToDate {
+		reasons = append(reasons, fmt.Sprintf("none of the %d source files is newer than %s", len(sources), newestName))
+	}
+	return upToDate, reasons, nil
+}
+
 func (checker *TimestampChecker) Kind() string {
 	return "timestamp"
 }
@@ -108,22 +160,25 @@ func (checker *TimestampChecker) Value(t *ast.Task) (any, error) {
 }
 
 func getMaxTime(files ...string) (time.Time, error) {
+	_, t, err := newestFile(files...)
+	return t, err
+}
+
+// newestFile returns the file that was modified last and its modification
+// time.
+func newestFile(files ...string) (string, time.Time, error) {
+	var newest string
 	var t time.Time
 	for _, f := range files {
 		info, err := os.Stat(f)
 		if err != nil {
-			return time.Time{}, err
+			return "", time.Time{}, err
+		}
+		if info.ModTime().After(t) {
+			newest, t = f, info.ModTime()
 		}
-		t = maxTime(t, info.ModTime())
-	}
-	return t, nil
-}
-
-func maxTime(a, b time.Time) time.Time {
-	if a.After(b) {
-		return a
 	}
-	return b
+	return newest, t, nil
 }
 
 // If the modification time of any of the files is newer than the the given time, returns true.
@@ -146,6 +201,19 @@ func (*TimestampChecker) OnError(t *ast.Task) error {
 	return nil
 }
 
-func (checker *TimestampChecker) timestampFilePath(t *ast.Task) string {
-	return filepath.Join(checker.tempDir, "timestamp", normalizeFilename(t.Task))
+// lastRun returns when the given task last ran, or the zero time if it never
+// did.
+func (checker *TimestampChecker) lastRun(t *ast.Task) (time.Time, error) {
+	e, err := checker.store.Get(t, checker.Kind())
+	if err != nil || e == nil || e.LastRun == nil {
+		return time.Time{}, err
+	}
+	return *e.LastRun, nil
+}
+
+func (checker *TimestampChecker) setLastRun(t *ast.Task, lastRun time.Time) error {
+	return checker.store.Update(t, checker.Kind(), func(e *Entry) bool {
+		e.LastRun = &lastRun
+		return true
+	})
 }
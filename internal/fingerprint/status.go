@@ -2,6 +2,7 @@ package fingerprint
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-task/task/v3/internal/env"
 	"github.com/go-task/task/v3/internal/execext"
@@ -34,3 +35,21 @@ func (checker *StatusChecker) IsUpToDate(ctx context.Context, t *ast.Task) (bool
 	}
 	return true, nil
 }
+
+// Explain runs the status commands of the given task until one of them fails
+// and tells which ones succeeded and which one failed.
+func (checker *StatusChecker) Explain(ctx context.Context, t *ast.Task) (bool, []string, error) {
+	var reasons []string
+	for _, s := range t.Status {
+		err := execext.RunCommand(ctx, &execext.RunCommandOptions{
+			Command: s,
+			Dir:     t.Dir,
+			Env:     env.Get(t),
+		})
+		if err != nil {
+			return false, append(reasons, fmt.Sprintf("status command %q failed: %v", s, err)), nil
+		}
+		reasons = append(reasons, fmt.Sprintf("status command %q succeeded", s))
+	}
+	return true, reasons, nil
+}
@@ -0,0 +1,245 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zeebo/xxh3"
+
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// A Store keeps the fingerprints of tasks in a single file. Each task has an
+// entry for each set of variables it was called with. The file is only changed
+// while holding a lock and is replaced atomically, so several Task processes
+// can share the same store.
+type Store struct {
+	dir string
+}
+
+// NewStore returns the store of fingerprints in the given directory.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// An Entry is the fingerprint of a task called with a set of variables.
+type Entry struct {
+	// Key identifies the entry. It is the name of the task followed by a hash
+	// of the variables it was called with, if any.
+	Key  string `json:"-"`
+	Task string `json:"task"`
+	// Vars are the names of the variables the task was called with and a
+	// short hash of their values. The values themselves are never stored,
+	// since they may be secrets.
+	Vars map[string]string `json:"vars,omitempty"`
+	// Method is the method that checked whether the task is up to date
+	Method  string    `json:"method"`
+	Updated time.Time `json:"updated"`
+	// Manifest is the manifest of the sources of the task, for the checksum
+	// and git methods
+	Manifest *manifest `json:"manifest,omitempty"`
+	// LastRun is when the task last ran, for the timestamp method
+	LastRun *time.Time `json:"last_run,omitempty"`
+}
+
+type storeFile struct {
+	Entries map[string]*Entry `json:"entries"`
+}
+
+// Get returns the entry of the given task stored by the given method, or nil
+// if there is none.
+func (s *Store) Get(t *ast.Task, method string) (*Entry, error) {
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	key, _ := entryKey(t)
+	e, ok := f.Entries[key]
+	if !ok || e.Method != method {
+		return nil, nil
+	}
+	return e, nil
+}
+
+// Update calls fn with the entry of the given task stored by the given method,
+// or with a new entry if there is none, and stores the entry if fn returns
+// true.
+func (s *Store) Update(t *ast.Task, method string, fn func(e *Entry) bool) error {
+	return s.change(func(f *storeFile) bool {
+		key, vars := entryKey(t)
+		e, ok := f.Entries[key]
+		if !ok || e.Method != method {
+			e = &Entry{Task: t.Task, Vars: vars, Method: method}
+		}
+		if !fn(e) {
+			return false
+		}
+		e.Updated = time.Now()
+		f.Entries[key] = e
+		return true
+	})
+}
+
+// Delete removes the entry of the given task.
+func (s *Store) Delete(t *ast.Task) error {
+	key, _ := entryKey(t)
+	return s.change(func(f *storeFile) bool {
+		if _, ok := f.Entries[key]; !ok {
+			return false
+		}
+		delete(f.Entries, key)
+		return true
+	})
+}
+
+// Clear removes the entries of the task with the given name, whatever the
+// variables it was called with, and returns how many were removed.
+func (s *Store) Clear(task string) (int, error) {
+	var n int
+	err := s.change(func(f *storeFile) bool {
+		for key, e := range f.Entries {
+			// Tasks with a label are stored by their label
+			if e.Task == task || key == task || strings.HasPrefix(key, task+" ") {
+				delete(f.Entries, key)
+				n++
+			}
+		}
+		return n > 0
+	})
+	return n, err
+}
+
+// List returns all the entries, sorted by key.
+func (s *Store) List() ([]*Entry, error) {
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*Entry, 0, len(f.Entries))
+	for _, key := range slices.Sorted(maps.Keys(f.Entries)) {
+		e := f.Entries[key]
+		e.Key = key
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (s *Store) path() string {
+	return filepath.Join(s.dir, "fingerprints.json")
+}
+
+// read reads the store. Since the file is replaced atomically, it doesn't
+// need the lock. A store that can't be decoded is treated as empty.
+func (s *Store) read() (*storeFile, error) {
+	f := &storeFile{}
+	data, err := os.ReadFile(s.path())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		_ = json.Unmarshal(data, f)
+	}
+	if f.Entries == nil {
+		f.Entries = map[string]*Entry{}
+	}
+	return f, nil
+}
+
+// change reads the store, calls fn with it and writes it back if fn returns
+// true, while holding the lock.
+func (s *Store) change(fn func(f *storeFile) bool) (err error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	lock, err := os.OpenFile(filepath.Join(s.dir, "fingerprints.lock"), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("task: unable to lock the fingerprint store: %w", err)
+	}
+	defer func() {
+		if unlockErr := unlockFile(lock); err == nil {
+			err = unlockErr
+		}
+	}()
+
+	f, err := s.read()
+	if err != nil {
+		return err
+	}
+	if !fn(f) {
+		return nil
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "fingerprints-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	// Temporary files are only readable by their owner, but the store may be
+	// shared with other users, like the checksum files before it
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path())
+}
+
+// entryKey returns the key of the entry of the given task and the hashed
+// variables it was called with.
+func entryKey(t *ast.Task) (string, map[string]string) {
+	values := map[string]string{}
+	for name, v := range t.CallVars.All() {
+		// MATCH is set for every call, but only tells something about the
+		// calls of tasks with wildcards
+		if wildcards, ok := v.Value.([]string); ok && name == "MATCH" && len(wildcards) == 0 {
+			continue
+		}
+		values[name] = varString(v)
+	}
+	if len(values) == 0 {
+		return t.Name(), nil
+	}
+	var pairs strings.Builder
+	vars := make(map[string]string, len(values))
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		fmt.Fprintf(&pairs, "%s=%s\x00", name, values[name])
+		vars[name] = shortHash(values[name])[:8]
+	}
+	return t.Name() + " " + shortHash(pairs.String()), vars
+}
+
+func shortHash(s string) string {
+	return fmt.Sprintf("%016x", xxh3.HashString(s))
+}
+
+func varString(v ast.Var) string {
+	switch {
+	case v.Value != nil:
+		return fmt.Sprint(v.Value)
+	case v.Sh != nil:
+		return "$(" + *v.Sh + ")"
+	case v.Ref != "":
+		return "ref:" + v.Ref
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package fingerprint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), ".task")
+	newTask := func(name string, vars ...string) *ast.Task {
+		task := &ast.Task{Task: name}
+		if len(vars) > 0 {
+			task.CallVars = ast.NewVars()
+			for i := 0; i < len(vars); i += 2 {
+				task.CallVars.Set(vars[i], ast.Var{Value: vars[i+1]})
+			}
+		}
+		return task
+	}
+	set := func(task *ast.Task) {
+		t.Helper()
+		require.NoError(t, NewStore(dir).Update(task, "checksum", func(e *Entry) bool {
+			e.Manifest = &manifest{Checksum: task.Name()}
+			return true
+		}))
+	}
+	keys := func() []string {
+		t.Helper()
+		entries, err := NewStore(dir).List()
+		require.NoError(t, err)
+		var keys []string
+		for _, e := range entries {
+			keys = append(keys, e.Key)
+		}
+		return keys
+	}
+	key := func(task *ast.Task) string {
+		k, _ := entryKey(task)
+		return k
+	}
+
+	// Names that differ only in punctuation and calls with different
+	// variables have their own entries
+	set(newTask("foo/bar"))
+	set(newTask("foo-bar"))
+	set(newTask("foo-bar", "OS", "linux"))
+	set(newTask("foo-bar", "OS", "windows", "MSG", "hello world"))
+	assert.ElementsMatch(t, []string{
+		"foo-bar",
+		key(newTask("foo-bar", "OS", "linux")),
+		key(newTask("foo-bar", "MSG", "hello world", "OS", "windows")),
+		"foo/bar",
+	}, keys())
+	assert.NotEqual(t, key(newTask("foo-bar", "OS", "linux")), key(newTask("foo-bar", "OS", "windows")))
+	assert.NotEqual(t, key(newTask("foo-bar", "A", "b=c")), key(newTask("foo-bar", "A", "b", "c", "")))
+
+	e, err := NewStore(dir).Get(newTask("foo-bar", "OS", "linux"), "checksum")
+	require.NoError(t, err)
+	require.NotNil(t, e)
+	assert.Equal(t, "foo-bar", e.Task)
+	require.Len(t, e.Vars, 1)
+	assert.Len(t, e.Vars["OS"], 8)
+
+	// The values of the variables are never written to the store
+	b, err := os.ReadFile(filepath.Join(dir, "fingerprints.json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "linux")
+	assert.NotContains(t, string(b), "hello world")
+
+	// Entries stored by another method are ignored
+	e, err = NewStore(dir).Get(newTask("foo-bar", "OS", "linux"), "timestamp")
+	require.NoError(t, err)
+	assert.Nil(t, e)
+
+	n, err := NewStore(dir).Clear("foo-bar")
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []string{"foo/bar"}, keys())
+
+	require.NoError(t, NewStore(dir).Delete(newTask("foo/bar")))
+	assert.Empty(t, keys())
+}
+
+func TestStoreConcurrentUpdates(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), ".task")
+
+	// Each store stands for a different Task process
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			task := &ast.Task{Task: fmt.Sprintf("task-%d", i)}
+			assert.NoError(t, NewStore(dir).Update(task, "checksum", func(e *Entry) bool {
+				e.Manifest = &manifest{Checksum: task.Task}
+				return true
+			}))
+		}()
+	}
+	wg.Wait()
+
+	entries, err := NewStore(dir).List()
+	require.NoError(t, err)
+	assert.Len(t, entries, 20)
+}
+
+func TestStoreFileMode(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on Windows")
+	}
+
+	dir := filepath.Join(t.TempDir(), ".task")
+	require.NoError(t, NewStore(dir).Update(&ast.Task{Task: "build"}, "checksum", func(e *Entry) bool {
+		return true
+	}))
+
+	info, err := os.Stat(filepath.Join(dir, "fingerprints.json"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+}
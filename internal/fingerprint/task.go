@@ -62,34 +62,10 @@ func IsTaskUpToDate(
 ) (bool, error) {
 	var statusUpToDate bool
 	var sourcesUpToDate bool
-	var err error
 
-	// Default config
-	config := &CheckerConfig{
-		method:         "none",
-		tempDir:        "",
-		dry:            false,
-		logger:         nil,
-		statusChecker:  nil,
-		sourcesChecker: nil,
-	}
-
-	// Apply functional options
-	for _, opt := range opts {
-		opt(config)
-	}
-
-	// If no status checker was given, set up the default one
-	if config.statusChecker == nil {
-		config.statusChecker = NewStatusChecker(config.logger)
-	}
-
-	// If no sources checker was given, set up the default one
-	if config.sourcesChecker == nil {
-		config.sourcesChecker, err = NewSourcesChecker(config.method, config.tempDir, config.dry)
-		if err != nil {
-			return false, err
-		}
+	config, err := newCheckerConfig(opts...)
+	if err != nil {
+		return false, err
 	}
 
 	statusIsSet := len(t.Status) != 0
@@ -130,3 +106,38 @@ func IsTaskUpToDate(
 	// i.e. it is never considered "up-to-date"
 	return false, nil
 }
+
+// newCheckerConfig applies the given functional options to the default config
+// and sets up the default checkers if none were given.
+func newCheckerConfig(opts ...CheckerOption) (*CheckerConfig, error) {
+	// Default config
+	config := &CheckerConfig{
+		method:         "none",
+		tempDir:        "",
+		dry:            false,
+		logger:         nil,
+		statusChecker:  nil,
+		sourcesChecker: nil,
+	}
+
+	// Apply functional options
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	// If no status checker was given, set up the default one
+	if config.statusChecker == nil {
+		config.statusChecker = NewStatusChecker(config.logger)
+	}
+
+	// If no sources checker was given, set up the default one
+	if config.sourcesChecker == nil {
+		var err error
+		config.sourcesChecker, err = NewSourcesChecker(config.method, config.tempDir, config.dry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
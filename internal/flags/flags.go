@@ -2,18 +2,27 @@ package flags
 
 import (
 	"cmp"
+	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
 
 	"github.com/go-task/task/v3"
 	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/artifact"
 	"github.com/go-task/task/v3/internal/env"
+	"github.com/go-task/task/v3/internal/execext"
 	"github.com/go-task/task/v3/internal/experiments"
+	"github.com/go-task/task/v3/internal/output"
+	"github.com/go-task/task/v3/internal/report"
 	"github.com/go-task/task/v3/internal/sort"
+	"github.com/go-task/task/v3/internal/taskgraph"
+	"github.com/go-task/task/v3/internal/taskrc"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
 
@@ -49,6 +58,7 @@ var (
 	ListJson    bool
 	TaskSort    string
 	Status      bool
+	Explain     bool
 	NoStatus    bool
 	Insecure    bool
 	Force       bool
@@ -61,18 +71,35 @@ var (
 	Summary     bool
 	ExitCode    bool
 	Parallel    bool
+	KeepGoing   bool
 	Concurrency int
 	Dir         string
 	Entrypoint  string
 	Output      ast.Output
 	Color       bool
 	Interval    time.Duration
+	KillTimeout time.Duration
 	Global      bool
 	Experiments bool
 	Download    bool
 	Offline     bool
 	ClearCache  bool
 	Timeout     time.Duration
+	Cache       bool
+	CacheSize   int64
+	Events      string
+	Trace       string
+	Timings     bool
+	Report      string
+	Timestamps  string
+	ReportFile  string
+	JUnit       string
+	Graph       string
+	Lint        bool
+	Strict      bool
+
+	FingerprintList  bool
+	FingerprintClear string
 )
 
 func init() {
@@ -95,27 +122,50 @@ func init() {
 	pflag.BoolVarP(&ListJson, "json", "j", false, "Formats task list as JSON.")
 	pflag.StringVar(&TaskSort, "sort", "", "Changes the order of the tasks when listed. [default|alphanumeric|none].")
 	pflag.BoolVar(&Status, "status", false, "Exits with non-zero exit code if any of the given tasks is not up-to-date.")
+	pflag.BoolVar(&Explain, "explain", false, "Explains why the given tasks are or aren't up-to-date, without running them.")
 	pflag.BoolVar(&NoStatus, "no-status", false, "Ignore status when listing tasks as JSON")
+	pflag.BoolVar(&FingerprintList, "fingerprint-list", false, "Lists the fingerprints stored for the tasks of the Taskfile.")
+	pflag.StringVar(&FingerprintClear, "fingerprint-clear", "", "Removes the fingerprints stored for the given task, so it runs again.")
 	pflag.BoolVar(&Insecure, "insecure", false, "Forces Task to download Taskfiles over insecure connections.")
 	pflag.BoolVarP(&Watch, "watch", "w", false, "Enables watch of the given task.")
 	pflag.BoolVarP(&Verbose, "verbose", "v", false, "Enables verbose mode.")
 	pflag.BoolVarP(&Silent, "silent", "s", false, "Disables echoing.")
 	pflag.BoolVarP(&AssumeYes, "yes", "y", false, "Assume \"yes\" as answer to all prompts.")
 	pflag.BoolVarP(&Parallel, "parallel", "p", false, "Executes tasks provided on command line in parallel.")
+	pflag.BoolVarP(&KeepGoing, "keep-going", "k", false, "Lets tasks that don't depend on a failed task finish and reports every failed task at the end.")
 	pflag.BoolVarP(&Dry, "dry", "n", false, "Compiles and prints tasks in the order that they would be run, without executing them.")
 	pflag.BoolVar(&Summary, "summary", false, "Show summary about a task.")
 	pflag.BoolVarP(&ExitCode, "exit-code", "x", false, "Pass-through the exit code of the task command.")
 	pflag.StringVarP(&Dir, "dir", "d", "", "Sets directory of execution.")
 	pflag.StringVarP(&Entrypoint, "taskfile", "t", "", `Choose which Taskfile to run. Defaults to "Taskfile.yml".`)
-	pflag.StringVarP(&Output.Name, "output", "o", "", "Sets output style: [interleaved|group|prefixed].")
+	pflag.StringVarP(&Output.Name, "output", "o", "", "Sets output style: [interleaved|group|prefixed|ci|files|progress].")
 	pflag.StringVar(&Output.Group.Begin, "output-group-begin", "", "Message template to print before a task's grouped output.")
 	pflag.StringVar(&Output.Group.End, "output-group-end", "", "Message template to print after a task's grouped output.")
 	pflag.BoolVar(&Output.Group.ErrorOnly, "output-group-error-only", false, "Swallow output from successful tasks.")
+	pflag.BoolVar(&Output.Group.ColorStderr, "output-group-color-stderr", false, "Colors the grouped output that commands write to stderr red.")
+	pflag.BoolVar(&Output.Prefixed.ColorStderr, "output-prefixed-color-stderr", false, "Colors the lines that commands write to stderr red.")
+	pflag.StringVar(&Output.Files.Dir, "output-files-dir", "", "Directory to write the log file of each task to, relative to the Taskfile.")
 	pflag.BoolVarP(&Color, "color", "c", true, "Colored output. Enabled by default. Set flag to false or use NO_COLOR=1 to disable.")
 	pflag.IntVarP(&Concurrency, "concurrency", "C", 0, "Limit number of tasks to run concurrently.")
 	pflag.DurationVarP(&Interval, "interval", "I", 0, "Interval to watch for changes.")
+	pflag.DurationVar(&KillTimeout, "kill-timeout", execext.DefaultKillTimeout, "Time to wait for commands to stop after forwarding a signal before killing them.")
 	pflag.BoolVarP(&Global, "global", "g", false, "Runs global Taskfile, from $HOME/{T,t}askfile.{yml,yaml}.")
 	pflag.BoolVar(&Experiments, "experiments", false, "Lists all the available experiments and whether or not they are enabled.")
+	pflag.BoolVar(&Cache, "cache", false, "Restores the generated files of tasks from a local cache instead of running them when possible.")
+	pflag.Int64Var(&CacheSize, "cache-max-size", 1024, "Maximum size of the local output cache in megabytes. Zero means unlimited.")
+	pflag.StringVar(&Events, "events", "", "Writes a stream of JSON execution events to the given file or file descriptor (fd:N).")
+	pflag.StringVar(&Graph, "graph", "", "Prints the graph of the given tasks, or all tasks, and their dependencies: [dot|mermaid|json].")
+	pflag.Lookup("graph").NoOptDefVal = taskgraph.FormatDOT
+	pflag.StringVar(&JUnit, "junit", "", "Writes a JUnit XML report of the tasks that were executed to the given file.")
+	pflag.BoolVar(&Lint, "lint", false, "Checks the Taskfile for common mistakes and exits with a non-zero exit code if any errors are found.")
+	pflag.BoolVar(&Strict, "strict", false, "Fails instead of warning when a Taskfile contains unknown keys.")
+	pflag.StringVar(&Report, "report", "", "Prints a report of every task that ran, was up-to-date, was skipped or failed after the run: [text|json].")
+	pflag.Lookup("report").NoOptDefVal = report.FormatText
+	pflag.StringVar(&ReportFile, "report-file", "", "Writes the report to the given file instead of stderr. Implies --report.")
+	pflag.BoolVar(&Timings, "timings", false, "Prints the time spent in each task and the critical path after the run.")
+	pflag.StringVar(&Timestamps, "timestamps", "", "Prepends a timestamp to every line printed by commands: [wall|elapsed].")
+	pflag.Lookup("timestamps").NoOptDefVal = output.TimestampsWall
+	pflag.StringVar(&Trace, "trace", "", "Writes a trace of the run in Chrome Trace Event format to the given file.")
 
 	// Gentle force experiment will override the force flag and add a new force-all flag
 	if experiments.GentleForce.Enabled() {
@@ -145,6 +195,22 @@ func Validate() error {
 		return errors.New("task: You can't set both --download and --clear-cache flags")
 	}
 
+	if CacheSize < 0 {
+		return errors.New("task: --cache-max-size can't be negative")
+	}
+
+	if Graph != "" && !slices.Contains(taskgraph.Formats, Graph) {
+		return fmt.Errorf("task: --graph must be one of %s", strings.Join(taskgraph.Formats, ", "))
+	}
+
+	if Report != "" && !slices.Contains(report.Formats, Report) {
+		return fmt.Errorf("task: --report must be one of %s", strings.Join(report.Formats, ", "))
+	}
+
+	if Timestamps != "" && !slices.Contains(output.TimestampFormats, Timestamps) {
+		return fmt.Errorf("task: --timestamps must be one of %s", strings.Join(output.TimestampFormats, ", "))
+	}
+
 	if Global && Dir != "" {
 		return errors.New("task: You can't set both --global and --dir")
 	}
@@ -159,20 +225,35 @@ func Validate() error {
 		if Output.Group.ErrorOnly {
 			return errors.New("task: You can't set --output-group-error-only without --output=group")
 		}
+		if Output.Group.ColorStderr {
+			return errors.New("task: You can't set --output-group-color-stderr without --output=group")
+		}
+	}
+
+	if Output.Name != "prefixed" && Output.Prefixed.ColorStderr {
+		return errors.New("task: You can't set --output-prefixed-color-stderr without --output=prefixed")
+	}
+
+	if Output.Name != "files" && Output.Files.Dir != "" {
+		return errors.New("task: You can't set --output-files-dir without --output=files")
 	}
 
 	if List && ListAll {
 		return errors.New("task: cannot use --list and --list-all at the same time")
 	}
 
-	if ListJson && !List && !ListAll {
-		return errors.New("task: --json only applies to --list or --list-all")
+	if ListJson && !List && !ListAll && !Lint {
+		return errors.New("task: --json only applies to --list, --list-all or --lint")
 	}
 
 	if NoStatus && !ListJson {
 		return errors.New("task: --no-status only applies to --json with --list or --list-all")
 	}
 
+	if FingerprintList && FingerprintClear != "" {
+		return errors.New("task: You can't set both --fingerprint-list and --fingerprint-clear")
+	}
+
 	return nil
 }
 
@@ -207,6 +288,7 @@ func WithExecutorOptions() task.ExecutorOption {
 			task.ExecutorWithDownload(Download),
 			task.ExecutorWithOffline(Offline),
 			task.ExecutorWithTimeout(Timeout),
+			task.ExecutorWithStrict(Strict || Lint),
 			task.ExecutorWithWatch(Watch),
 			task.ExecutorWithVerbose(Verbose),
 			task.ExecutorWithSilent(Silent),
@@ -214,12 +296,34 @@ func WithExecutorOptions() task.ExecutorOption {
 			task.ExecutorWithDry(Dry || Status),
 			task.ExecutorWithSummary(Summary),
 			task.ExecutorWithParallel(Parallel),
+			task.ExecutorWithKeepGoing(KeepGoing),
 			task.ExecutorWithColor(Color),
 			task.ExecutorWithConcurrency(Concurrency),
 			task.ExecutorWithInterval(Interval),
+			task.ExecutorWithKillTimeout(KillTimeout),
 			task.ExecutorWithOutputStyle(Output),
 			task.ExecutorWithTaskSorter(sorter),
+			task.ExecutorWithCache(Cache),
+			task.ExecutorWithCacheMaxSize(CacheSize*1024*1024),
+			task.ExecutorWithRemoteCache(remoteCache()),
+			task.ExecutorWithTimings(Timings),
+			task.ExecutorWithReport(Report, nil),
+			task.ExecutorWithTimestamps(Timestamps),
 			task.ExecutorWithVersionCheck(true),
 		)
 	}
 }
+
+// remoteCache returns the remote cache configured in the .taskrc.yml file, if
+// any.
+func remoteCache() artifact.Remote {
+	cfg := taskrc.Get().RemoteCache
+	if cfg == nil || cfg.URL == "" {
+		return nil
+	}
+	var remote artifact.Remote = artifact.NewHTTPRemote(cfg.URL, cfg.Headers, artifact.HTTPRemoteWithTimeout(cfg.Timeout))
+	if cfg.ReadOnly {
+		remote = artifact.ReadOnly(remote)
+	}
+	return remote
+}
@@ -0,0 +1,272 @@
+// Package junit records the tasks of a run as test cases and writes them as a
+// JUnit XML report, which most CI systems can show.
+package junit
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"mvdan.cc/sh/v3/interp"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/output"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+type (
+	// A Recorder collects a test case for every task executed during a run. It
+	// is safe for concurrent use. All methods are no-ops on a nil Recorder, so
+	// callers don't need to check whether the report is enabled.
+	Recorder struct {
+		mutex sync.Mutex
+		start time.Time
+		cases []*Case
+	}
+	// A Case is a single task execution. All methods are no-ops on a nil Case.
+	Case struct {
+		Name      string
+		ClassName string
+		Start     time.Time
+		End       time.Time
+		// Skipped is the reason the task didn't run its commands, if any.
+		Skipped string
+		// Err is the error the task failed with, if any.
+		Err error
+
+		mutex  sync.Mutex
+		stdout buffer
+		stderr buffer
+	}
+)
+
+type caseKey struct{}
+
+// New creates a new [Recorder].
+func New() *Recorder {
+	return &Recorder{start: time.Now()}
+}
+
+// Start records the start of the given task. The returned context should be
+// passed to any work done by the task, so that the output of its commands is
+// captured by [Case.Wrap].
+func (r *Recorder) Start(ctx context.Context, t *ast.Task) (context.Context, *Case) {
+	if r == nil {
+		return ctx, nil
+	}
+	c := &Case{
+		Name:      t.LocalName(),
+		ClassName: strings.ReplaceAll(t.Namespace, ast.NamespaceSeparator, "."),
+		Start:     time.Now(),
+	}
+	c.stdout.mutex = &c.mutex
+	c.stderr.mutex = &c.mutex
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.cases = append(r.cases, c)
+
+	return context.WithValue(ctx, caseKey{}, c), c
+}
+
+// FromContext returns the case of the task that is running in ctx, if any.
+func FromContext(ctx context.Context) *Case {
+	c, _ := ctx.Value(caseKey{}).(*Case)
+	return c
+}
+
+// Skip marks the task as skipped for the given reason.
+func (c *Case) Skip(reason string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.Skipped = reason
+}
+
+// Finish records the end of the task and the error it failed with, if any.
+func (c *Case) Finish(err error) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.End = time.Now()
+	c.Err = err
+}
+
+// Wrap returns an [output.Output] that captures the output of the commands of
+// the task in addition to writing it with o.
+func (c *Case) Wrap(o output.Output) output.Output {
+	if c == nil {
+		return o
+	}
+	return output.Tee{Output: o, Stdout: &c.stdout, Stderr: &c.stderr}
+}
+
+// buffer is a [bytes.Buffer] that is safe for concurrent use.
+type buffer struct {
+	mutex *sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *buffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+type (
+	xmlTestSuites struct {
+		XMLName xml.Name       `xml:"testsuites"`
+		Suites  []xmlTestSuite `xml:"testsuite"`
+	}
+	xmlTestSuite struct {
+		Name      string        `xml:"name,attr"`
+		Tests     int           `xml:"tests,attr"`
+		Failures  int           `xml:"failures,attr"`
+		Errors    int           `xml:"errors,attr"`
+		Skipped   int           `xml:"skipped,attr"`
+		Time      string        `xml:"time,attr"`
+		Timestamp string        `xml:"timestamp,attr"`
+		Cases     []xmlTestCase `xml:"testcase"`
+	}
+	xmlTestCase struct {
+		Name      string      `xml:"name,attr"`
+		ClassName string      `xml:"classname,attr,omitempty"`
+		Time      string      `xml:"time,attr"`
+		Skipped   *xmlMessage `xml:"skipped"`
+		Failure   *xmlMessage `xml:"failure"`
+		Error     *xmlMessage `xml:"error"`
+		SystemOut *xmlOutput  `xml:"system-out"`
+		SystemErr *xmlOutput  `xml:"system-err"`
+	}
+	xmlOutput struct {
+		Text string `xml:",cdata"`
+	}
+	xmlMessage struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr,omitempty"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// Write writes the recorded cases to w as a JUnit XML report. Tasks whose
+// commands exited with a non-zero exit code are reported as failures, tasks
+// that failed for any other reason as errors and tasks that were up to date as
+// skipped.
+func (r *Recorder) Write(w io.Writer) error {
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	suite := xmlTestSuite{
+		Name:      "task",
+		Tests:     len(r.cases),
+		Time:      seconds(time.Since(r.start)),
+		Timestamp: r.start.Format(time.RFC3339),
+		Cases:     make([]xmlTestCase, 0, len(r.cases)),
+	}
+	for _, c := range r.cases {
+		suite.Cases = append(suite.Cases, c.xml(&suite))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(xmlTestSuites{Suites: []xmlTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func (c *Case) xml(suite *xmlTestSuite) xmlTestCase {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	end := c.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	tc := xmlTestCase{
+		Name:      c.Name,
+		ClassName: c.ClassName,
+		Time:      seconds(end.Sub(c.Start)),
+		SystemOut: newXMLOutput(&c.stdout),
+		SystemErr: newXMLOutput(&c.stderr),
+	}
+	switch {
+	case c.Err != nil:
+		if exitCode, ok := exitCode(c.Err); ok {
+			suite.Failures++
+			tc.Failure = &xmlMessage{
+				Message: sanitize(c.Err.Error()),
+				Type:    fmt.Sprintf("exit code %d", exitCode),
+			}
+		} else {
+			suite.Errors++
+			tc.Error = &xmlMessage{Message: sanitize(c.Err.Error())}
+		}
+	case c.Skipped != "":
+		suite.Skipped++
+		tc.Skipped = &xmlMessage{Message: c.Skipped}
+	}
+	return tc
+}
+
+func newXMLOutput(b *buffer) *xmlOutput {
+	if b.buf.Len() == 0 {
+		return nil
+	}
+	return &xmlOutput{Text: sanitize(b.buf.String())}
+}
+
+// ansiRegexp matches ANSI CSI sequences (like colors), OSC sequences (like
+// hyperlinks and window titles) and other two-character escape sequences.
+var ansiRegexp = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[@-Z\\-_])`)
+
+// sanitize removes ANSI escape sequences from s and replaces any other
+// characters that are not allowed in XML, including invalid UTF-8, with
+// U+FFFD. [xml.Encoder] doesn't do this for CDATA sections, so without it
+// the output of commands could produce a report that parsers reject.
+func sanitize(s string) string {
+	s = ansiRegexp.ReplaceAllString(s, "")
+	return strings.Map(func(r rune) rune {
+		if isXMLChar(r) {
+			return r
+		}
+		return '\uFFFD'
+	}, s)
+}
+
+// isXMLChar reports whether r is in the Char range of the XML specification.
+func isXMLChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		(r >= 0x20 && r <= 0xD7FF) ||
+		(r >= 0xE000 && r <= 0xFFFD) ||
+		(r >= 0x10000 && r <= 0x10FFFF)
+}
+
+func exitCode(err error) (uint8, bool) {
+	var runErr *errors.TaskRunError
+	if errors.As(err, &runErr) {
+		err = runErr.Err
+	}
+	return interp.IsExitStatus(err)
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,128 @@
+package junit_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mvdan.cc/sh/v3/interp"
+
+	"github.com/go-task/task/v3/internal/junit"
+	"github.com/go-task/task/v3/internal/output"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	r := junit.New()
+	ctx, build := r.Start(context.Background(), &ast.Task{Task: "lib:build", Namespace: "lib"})
+	stdOut, stdErr, closer := junit.FromContext(ctx).Wrap(output.Group{}).WrapWriter(io.Discard, io.Discard, "", nil)
+	fmt.Fprintln(stdOut, "out")
+	fmt.Fprintln(stdErr, "err")
+	require.NoError(t, closer(nil))
+	build.Finish(nil)
+
+	_, generate := r.Start(context.Background(), &ast.Task{Task: "generate"})
+	generate.Skip("up to date")
+	generate.Finish(nil)
+
+	_, test := r.Start(context.Background(), &ast.Task{Task: "test"})
+	test.Finish(interp.NewExitStatus(3))
+
+	_, lint := r.Start(context.Background(), &ast.Task{Task: "lint"})
+	lint.Finish(errors.New("precondition not met"))
+
+	var buff bytes.Buffer
+	require.NoError(t, r.Write(&buff))
+
+	var report struct {
+		Suites []struct {
+			Tests    int `xml:"tests,attr"`
+			Failures int `xml:"failures,attr"`
+			Errors   int `xml:"errors,attr"`
+			Skipped  int `xml:"skipped,attr"`
+			Cases    []struct {
+				Name      string    `xml:"name,attr"`
+				ClassName string    `xml:"classname,attr"`
+				Skipped   *struct{} `xml:"skipped"`
+				Failure   *struct {
+					Type string `xml:"type,attr"`
+				} `xml:"failure"`
+				Error     *struct{} `xml:"error"`
+				SystemOut string    `xml:"system-out"`
+				SystemErr string    `xml:"system-err"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal(buff.Bytes(), &report))
+	require.Len(t, report.Suites, 1)
+	suite := report.Suites[0]
+	assert.Equal(t, 4, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, 1, suite.Errors)
+	assert.Equal(t, 1, suite.Skipped)
+	require.Len(t, suite.Cases, 4)
+
+	assert.Equal(t, "build", suite.Cases[0].Name)
+	assert.Equal(t, "lib", suite.Cases[0].ClassName)
+	assert.Equal(t, "out\n", suite.Cases[0].SystemOut)
+	assert.Equal(t, "err\n", suite.Cases[0].SystemErr)
+	assert.NotNil(t, suite.Cases[1].Skipped)
+	require.NotNil(t, suite.Cases[2].Failure)
+	assert.Equal(t, "exit code 3", suite.Cases[2].Failure.Type)
+	assert.NotNil(t, suite.Cases[3].Error)
+}
+
+func TestRecorderSanitizesOutput(t *testing.T) {
+	t.Parallel()
+
+	r := junit.New()
+	ctx, build := r.Start(context.Background(), &ast.Task{Task: "build"})
+	stdOut, stdErr, closer := junit.FromContext(ctx).Wrap(output.Group{}).WrapWriter(io.Discard, io.Discard, "", nil)
+	fmt.Fprint(stdOut, "\x1b[1;32mok\x1b[0m \x1b]8;;https://taskfile.dev\x1b\\link\x1b]8;;\x1b\\\n")
+	fmt.Fprint(stdErr, "bell\a nul\x00 ]]> \xff\n")
+	require.NoError(t, closer(nil))
+	build.Finish(errors.New("\x1b[31mfailed\x1b[0m"))
+
+	var buff bytes.Buffer
+	require.NoError(t, r.Write(&buff))
+
+	var report struct {
+		Suites []struct {
+			Cases []struct {
+				Error *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"error"`
+				SystemOut string `xml:"system-out"`
+				SystemErr string `xml:"system-err"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal(buff.Bytes(), &report))
+	require.Len(t, report.Suites, 1)
+	require.Len(t, report.Suites[0].Cases, 1)
+	c := report.Suites[0].Cases[0]
+	assert.Equal(t, "ok link\n", c.SystemOut)
+	assert.Equal(t, "bell\uFFFD nul\uFFFD ]]> \uFFFD\n", c.SystemErr)
+	require.NotNil(t, c.Error)
+	assert.Equal(t, "failed", c.Error.Message)
+}
+
+func TestNilRecorder(t *testing.T) {
+	t.Parallel()
+
+	var r *junit.Recorder
+	ctx, c := r.Start(context.Background(), &ast.Task{Task: "build"})
+	assert.Nil(t, c)
+	assert.Nil(t, junit.FromContext(ctx))
+	assert.Equal(t, output.Interleaved{}, c.Wrap(output.Interleaved{}))
+	c.Finish(nil)
+	require.NoError(t, r.Write(io.Discard))
+}
@@ -0,0 +1,144 @@
+// Package lint holds the issues found when checking a Taskfile for common
+// mistakes and writes them in a human-readable or JSON format.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/taskfile"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// Severity describes how serious an [Issue] is. Only errors cause the linter
+// to exit with a non-zero exit code.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Names of the rules that the linter checks.
+const (
+	RuleDecode                  = "decode"
+	RuleUnknownKey              = "unknown-key"
+	RuleMissingTask             = "missing-task"
+	RuleUnusedInternal          = "unused-internal"
+	RuleMissingRequiredVar      = "missing-required-var"
+	RuleGeneratesWithoutSources = "generates-without-sources"
+	RuleInvalidMethod           = "invalid-method"
+	RuleInvalidRun              = "invalid-run"
+	RuleDuplicateAlias          = "duplicate-alias"
+	RuleInvalidPlatform         = "invalid-platform"
+)
+
+// An Issue is a single problem found in a Taskfile.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	Message  string   `json:"message"`
+	Task     string   `json:"task,omitempty"`
+	Taskfile string   `json:"taskfile"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	// Snippet is the highlighted part of the Taskfile that the issue refers
+	// to. It is only included in the human-readable output.
+	Snippet string `json:"-"`
+}
+
+// FromError converts an error returned while reading a Taskfile into an
+// issue, if the error points at a location in the Taskfile. Errors like these
+// stop the Taskfile from being read, so they can't be found by walking it.
+func FromError(err error) (*Issue, bool) {
+	var decodeErr *errors.TaskfileDecodeError
+	if !errors.As(err, &decodeErr) {
+		return nil, false
+	}
+	issue := &Issue{
+		Severity: SeverityError,
+		Rule:     RuleDecode,
+		Message:  decodeErr.Message,
+		Taskfile: decodeErr.Location,
+		Line:     decodeErr.Line,
+		Column:   decodeErr.Column,
+		Snippet:  decodeErr.Snippet,
+	}
+	var platformErr *ast.ErrInvalidPlatform
+	switch {
+	case errors.As(err, &platformErr):
+		issue.Rule = RuleInvalidPlatform
+		issue.Message = platformErr.Error()
+	case errors.Is(err, taskfile.ErrUnknownKey):
+		issue.Rule = RuleUnknownKey
+	}
+	if issue.Message == "" && decodeErr.Err != nil {
+		issue.Message = decodeErr.Err.Error()
+	}
+	return issue, true
+}
+
+// Counts returns the number of errors and warnings in the given issues.
+func Counts(issues []*Issue) (errs int, warnings int) {
+	for _, issue := range issues {
+		switch issue.Severity {
+		case SeverityError:
+			errs++
+		case SeverityWarning:
+			warnings++
+		}
+	}
+	return errs, warnings
+}
+
+// Err returns a [errors.TaskfileLintError] if any of the given issues is an
+// error, or nil otherwise.
+func Err(issues []*Issue) error {
+	errs, warnings := Counts(issues)
+	if errs == 0 {
+		return nil
+	}
+	return &errors.TaskfileLintError{Errors: errs, Warnings: warnings}
+}
+
+// Write writes the given issues to w in a human-readable format.
+func Write(w io.Writer, issues []*Issue) error {
+	var b strings.Builder
+	for _, issue := range issues {
+		header := fmt.Sprintf("%s: %s [%s]", issue.Severity, issue.Message, issue.Rule)
+		if issue.Severity == SeverityError {
+			b.WriteString(color.RedString("%s", header))
+		} else {
+			b.WriteString(color.YellowString("%s", header))
+		}
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "file: %s:%d:%d\n", filepathext.TryAbsToRel(issue.Taskfile), issue.Line, issue.Column)
+		if issue.Snippet != "" {
+			b.WriteString(strings.TrimSuffix(issue.Snippet, "\n"))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the given issues to w as a JSON object.
+func WriteJSON(w io.Writer, issues []*Issue) error {
+	if issues == nil {
+		issues = []*Issue{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Issues []*Issue `json:"issues"`
+	}{
+		Issues: issues,
+	})
+}
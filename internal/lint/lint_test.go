@@ -0,0 +1,50 @@
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/internal/lint"
+)
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	var buff bytes.Buffer
+	require.NoError(t, lint.WriteJSON(&buff, nil))
+	assert.JSONEq(t, `{"issues": []}`, buff.String())
+
+	buff.Reset()
+	require.NoError(t, lint.WriteJSON(&buff, []*lint.Issue{{
+		Severity: lint.SeverityWarning,
+		Rule:     lint.RuleUnusedInternal,
+		Message:  "internal task \"foo\" is never called by another task",
+		Task:     "foo",
+		Taskfile: "Taskfile.yml",
+		Line:     3,
+		Column:   3,
+		Snippet:  "not included",
+	}}))
+	var out struct {
+		Issues []map[string]any `json:"issues"`
+	}
+	require.NoError(t, json.Unmarshal(buff.Bytes(), &out))
+	require.Len(t, out.Issues, 1)
+	assert.Equal(t, "warning", out.Issues[0]["severity"])
+	assert.NotContains(t, out.Issues[0], "snippet")
+}
+
+func TestErr(t *testing.T) {
+	t.Parallel()
+
+	warning := &lint.Issue{Severity: lint.SeverityWarning}
+	assert.NoError(t, lint.Err([]*lint.Issue{warning}))
+
+	err := lint.Err([]*lint.Issue{warning, {Severity: lint.SeverityError}})
+	require.Error(t, err)
+	assert.Equal(t, "task: Found 1 error and 1 warning in the Taskfile", err.Error())
+}
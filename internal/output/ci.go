@@ -0,0 +1,209 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// CI systems that the CI output style knows how to write to
+const (
+	CIGitHubActions = "github-actions"
+	CIGitLab        = "gitlab"
+	CIBuildkite     = "buildkite"
+)
+
+// A TaskWrapper is an [Output] that also wraps the output of all the commands
+// of a task together. It's given the variables the task was called with.
+type TaskWrapper interface {
+	WrapTask(stdOut, stdErr io.Writer, t *ast.Task, vars *ast.Vars) (io.Writer, io.Writer, CloseFunc)
+}
+
+// An Annotator is an [Output] that can point out a failed command of a task in
+// the system that the output is shown in.
+type Annotator interface {
+	Annotate(w io.Writer, t *ast.Task, cmd string, err error)
+}
+
+// CI is an [Output] that groups the output of each task in a collapsible
+// section of the log of the CI system that Task runs in. Commands that fail
+// are annotated with the location of their task in the Taskfile, if the CI
+// system supports it. Outside a known CI system, it behaves like [Group].
+type CI struct {
+	// System is the detected CI system, or empty if none was detected.
+	System    string
+	Workspace string
+	sections  atomic.Uint64
+	// mutex makes sure that sections of tasks running in parallel don't end
+	// up inside each other
+	mutex sync.Mutex
+}
+
+// NewCI creates a new [CI] output for the CI system detected from the given
+// environment.
+func NewCI(getenv func(string) string) *CI {
+	ci := &CI{}
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		ci.System = CIGitHubActions
+		ci.Workspace = getenv("GITHUB_WORKSPACE")
+	case getenv("GITLAB_CI") == "true":
+		ci.System = CIGitLab
+	case getenv("BUILDKITE") == "true":
+		ci.System = CIBuildkite
+	}
+	return ci
+}
+
+// WrapWriter doesn't wrap the output of commands, since the output of the
+// whole task is already wrapped by [CI.WrapTask].
+func (ci *CI) WrapWriter(stdOut, stdErr io.Writer, _ string, _ *templater.Cache) (io.Writer, io.Writer, CloseFunc) {
+	return stdOut, stdErr, func(error) error { return nil }
+}
+
+func (ci *CI) WrapTask(stdOut, stdErr io.Writer, t *ast.Task, _ *ast.Vars) (io.Writer, io.Writer, CloseFunc) {
+	cw := &ciWriter{writer: stdOut, errWriter: stdErr, start: time.Now()}
+	return &ciStream{cw: cw, buff: &cw.buff}, &ciStream{cw: cw, buff: &cw.errBuff}, func(err error) error {
+		return cw.close(ci, t.Name(), err)
+	}
+}
+
+// ciWriter buffers the output of both streams of a task until it finishes.
+type ciWriter struct {
+	writer    io.Writer
+	errWriter io.Writer
+	mutex     sync.Mutex
+	buff      bytes.Buffer
+	errBuff   bytes.Buffer
+	start     time.Time
+}
+
+// ciStream writes to one of the buffers of a [ciWriter].
+type ciStream struct {
+	cw   *ciWriter
+	buff *bytes.Buffer
+}
+
+func (s *ciStream) Write(p []byte) (int, error) {
+	s.cw.mutex.Lock()
+	defer s.cw.mutex.Unlock()
+	return s.buff.Write(p)
+}
+
+// close writes the buffered output of both streams to their own writers, with
+// the markers of the section around them written to stdout.
+func (cw *ciWriter) close(ci *CI, title string, err error) error {
+	cw.mutex.Lock()
+	defer cw.mutex.Unlock()
+	if cw.buff.Len() == 0 && cw.errBuff.Len() == 0 {
+		return nil
+	}
+	for _, buff := range []*bytes.Buffer{&cw.buff, &cw.errBuff} {
+		if buff.Len() > 0 && !bytes.HasSuffix(buff.Bytes(), []byte("\n")) {
+			buff.WriteString("\n")
+		}
+	}
+
+	var begin, end string
+	switch ci.System {
+	case CIGitHubActions:
+		begin = fmt.Sprintf("::group::%s\n", title)
+		end = "::endgroup::\n"
+	case CIGitLab:
+		// Sections of failed tasks are left expanded
+		name := fmt.Sprintf("task_%d_%s", ci.sections.Add(1), gitLabSectionName(title))
+		begin = fmt.Sprintf("\x1b[0Ksection_start:%d:%s[collapsed=%t]\r\x1b[0K%s\n", cw.start.Unix(), name, err == nil, title)
+		end = fmt.Sprintf("\x1b[0Ksection_end:%d:%s\r\x1b[0K\n", time.Now().Unix(), name)
+	case CIBuildkite:
+		// Groups of failed tasks are left expanded. Buildkite groups
+		// don't need to be ended, they last until the next one starts.
+		marker := "---"
+		if err != nil {
+			marker = "+++"
+		}
+		begin = fmt.Sprintf("%s %s\n", marker, title)
+	}
+
+	ci.mutex.Lock()
+	defer ci.mutex.Unlock()
+	if _, err := io.WriteString(cw.writer, begin+cw.buff.String()); err != nil {
+		return err
+	}
+	if cw.errBuff.Len() > 0 {
+		if _, err := cw.errWriter.Write(cw.errBuff.Bytes()); err != nil {
+			return err
+		}
+	}
+	_, writeErr := io.WriteString(cw.writer, end)
+	return writeErr
+}
+
+var gitLabSectionNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+func gitLabSectionName(title string) string {
+	return gitLabSectionNameRegexp.ReplaceAllString(title, "_")
+}
+
+// Annotate writes an error annotation for the given failed command of the
+// given task to w. Only GitHub Actions supports annotations that point to a
+// file, so nothing is written for other CI systems.
+func (ci *CI) Annotate(w io.Writer, t *ast.Task, cmd string, err error) {
+	if ci.System != CIGitHubActions {
+		return
+	}
+	properties := []string{"title=" + escapeGitHubProperty(fmt.Sprintf("task: %s", t.Name()))}
+	if t.Location != nil && t.Location.Taskfile != "" {
+		properties = append(properties,
+			"file="+escapeGitHubProperty(ci.relPath(t.Location.Taskfile)),
+			fmt.Sprintf("line=%d", t.Location.Line),
+			fmt.Sprintf("col=%d", t.Location.Column),
+		)
+	}
+	message := fmt.Sprintf("Command %q failed: %v", cmd, err)
+	fmt.Fprintf(w, "::error %s::%s\n", strings.Join(properties, ","), escapeGitHubData(message))
+}
+
+// relPath returns the given path relative to the workspace, since that's what
+// GitHub Actions expects annotations to point to.
+func (ci *CI) relPath(path string) string {
+	if ci.Workspace == "" {
+		return path
+	}
+	rel, err := filepath.Rel(ci.Workspace, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+var (
+	gitHubDataReplacer = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+	)
+	gitHubPropertyReplacer = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+		":", "%3A",
+		",", "%2C",
+	)
+)
+
+func escapeGitHubData(s string) string {
+	return gitHubDataReplacer.Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return gitHubPropertyReplacer.Replace(s)
+}
@@ -0,0 +1,124 @@
+package output
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// DefaultFilesDir is the directory that the files output writes the logs of
+// tasks to when none is given, relative to the root Taskfile.
+const DefaultFilesDir = ".task/logs"
+
+// Files is an [Output] that writes the output of each task to its own log file
+// in Dir, named after the task and the variables it was called with. Only the
+// status lines of Task itself are printed to the terminal, together with the
+// path of the log file of a task when it fails.
+type Files struct {
+	Dir    string
+	logger *logger.Logger
+	mutex  sync.Mutex
+	opened map[string]bool
+}
+
+func NewFiles(dir string, logger *logger.Logger) *Files {
+	return &Files{
+		Dir:    dir,
+		logger: logger,
+		opened: make(map[string]bool),
+	}
+}
+
+// WrapWriter doesn't wrap the output of commands, since the output of the
+// whole task is already wrapped by [Files.WrapTask].
+func (f *Files) WrapWriter(stdOut, stdErr io.Writer, _ string, _ *templater.Cache) (io.Writer, io.Writer, CloseFunc) {
+	return stdOut, stdErr, func(error) error { return nil }
+}
+
+func (f *Files) WrapTask(stdOut, stdErr io.Writer, t *ast.Task, vars *ast.Vars) (io.Writer, io.Writer, CloseFunc) {
+	path := filepath.Join(f.Dir, LogFileName(t, vars))
+	file, err := f.open(path)
+	if err != nil {
+		f.logger.FOutf(stdErr, logger.Yellow, "task: [%s] unable to write output to %s: %v\n", t.Name(), path, err)
+		return stdOut, stdErr, func(error) error { return nil }
+	}
+	return file, file, func(err error) error {
+		if err != nil {
+			f.logger.FOutf(stdErr, logger.Red, "task: [%s] output written to %s\n", t.Name(), displayPath(path))
+		}
+		return file.Close()
+	}
+}
+
+// open opens the log file at the given path. A task that runs more than once
+// with the same variables appends to the log file of its earlier runs, but
+// log files of previous runs of Task are truncated.
+func (f *Files) open(path string) (*os.File, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	flag := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if !f.opened[path] {
+		flag |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, flag, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	f.opened[path] = true
+	return file, nil
+}
+
+// displayPath returns the given path relative to the working directory if it's
+// inside of it, since that's easier to read and to copy.
+func displayPath(path string) string {
+	if rel := filepathext.TryAbsToRel(path); !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+var logFileNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9._=-]+`)
+
+// maxLogFileNameLength keeps the names of log files well below the limits of
+// file systems, even if a task is called with long variables.
+const maxLogFileNameLength = 128
+
+// LogFileName returns the name of the log file of the given task when it's
+// called with the given variables.
+func LogFileName(t *ast.Task, vars *ast.Vars) string {
+	var b strings.Builder
+	b.WriteString(t.Task)
+	for k, v := range vars.All() {
+		value := fmt.Sprint(v.Value)
+		// The wildcards of a task are passed as a list in MATCH
+		if wildcards, ok := v.Value.([]string); ok {
+			value = strings.Join(wildcards, "_")
+		}
+		if value == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "_%s=%s", k, value)
+	}
+	// Names that had to be changed or shortened get a hash of the original
+	// one, so that tasks like a:b and a_b don't share the same log file
+	name := logFileNameRegexp.ReplaceAllString(b.String(), "_")
+	if name != b.String() || len(name) > maxLogFileNameLength {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(b.String()))
+		name = fmt.Sprintf("%s_%08x", name[:min(len(name), maxLogFileNameLength-9)], h.Sum32())
+	}
+	return name + ".log"
+}
@@ -4,23 +4,31 @@ import (
 	"bytes"
 	"io"
 
+	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/internal/templater"
 )
 
 type Group struct {
 	Begin, End string
 	ErrorOnly  bool
+	// ColorStderr colors the output that commands write to stderr red, using
+	// Logger.
+	ColorStderr bool
+	Logger      *logger.Logger
 }
 
-func (g Group) WrapWriter(stdOut, _ io.Writer, _ string, cache *templater.Cache) (io.Writer, io.Writer, CloseFunc) {
-	gw := &groupWriter{writer: stdOut}
+func (g Group) WrapWriter(stdOut, stdErr io.Writer, _ string, cache *templater.Cache) (io.Writer, io.Writer, CloseFunc) {
+	gw := &groupWriter{writer: stdOut, errWriter: stdErr}
+	if g.ColorStderr && g.Logger != nil {
+		gw.errWriter = &colorWriter{writer: stdErr, logger: g.Logger, color: logger.Red}
+	}
 	if g.Begin != "" {
 		gw.begin = templater.Replace(g.Begin, cache) + "\n"
 	}
 	if g.End != "" {
 		gw.end = templater.Replace(g.End, cache) + "\n"
 	}
-	return gw, gw, func(err error) error {
+	return gw, &gw.errBuff, func(err error) error {
 		if g.ErrorOnly && err == nil {
 			return nil
 		}
@@ -31,7 +39,9 @@ func (g Group) WrapWriter(stdOut, _ io.Writer, _ string, cache *templater.Cache)
 
 type groupWriter struct {
 	writer     io.Writer
+	errWriter  io.Writer
 	buff       bytes.Buffer
+	errBuff    bytes.Buffer
 	begin, end string
 }
 
@@ -39,15 +49,36 @@ func (gw *groupWriter) Write(p []byte) (int, error) {
 	return gw.buff.Write(p)
 }
 
+// close writes the buffered output of both streams to their own writers, with
+// the begin and end messages around them written to stdout.
 func (gw *groupWriter) close() error {
-	if gw.buff.Len() == 0 {
+	if gw.buff.Len() == 0 && gw.errBuff.Len() == 0 {
 		// don't print begin/end messages if there's no buffered entries
 		return nil
 	}
 	if _, err := io.WriteString(gw.writer, gw.begin); err != nil {
 		return err
 	}
-	gw.buff.WriteString(gw.end)
-	_, err := io.Copy(gw.writer, &gw.buff)
+	if _, err := io.Copy(gw.writer, &gw.buff); err != nil {
+		return err
+	}
+	if gw.errBuff.Len() > 0 {
+		if _, err := io.Copy(gw.errWriter, &gw.errBuff); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(gw.writer, gw.end)
 	return err
 }
+
+// colorWriter writes everything in the given color.
+type colorWriter struct {
+	writer io.Writer
+	logger *logger.Logger
+	color  logger.Color
+}
+
+func (cw *colorWriter) Write(p []byte) (int, error) {
+	cw.logger.FOutf(cw.writer, cw.color, string(p))
+	return len(p), nil
+}
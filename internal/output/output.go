@@ -1,11 +1,14 @@
 package output
 
 import (
+	"cmp"
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/internal/term"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
 
@@ -24,16 +27,42 @@ func BuildFor(o *ast.Output, logger *logger.Logger) (Output, error) {
 		}
 		return Interleaved{}, nil
 	case "group":
+		if o.Prefixed.IsSet() {
+			return nil, fmt.Errorf("task: output style %q does not support the prefixed color stderr parameter", o.Name)
+		}
 		return Group{
-			Begin:     o.Group.Begin,
-			End:       o.Group.End,
-			ErrorOnly: o.Group.ErrorOnly,
+			Begin:       o.Group.Begin,
+			End:         o.Group.End,
+			ErrorOnly:   o.Group.ErrorOnly,
+			ColorStderr: o.Group.ColorStderr,
+			Logger:      logger,
 		}, nil
 	case "prefixed":
 		if err := checkOutputGroupUnset(o); err != nil {
 			return nil, err
 		}
-		return NewPrefixed(logger), nil
+		prefixed := NewPrefixed(logger)
+		prefixed.ColorStderr = o.Prefixed.ColorStderr
+		return prefixed, nil
+	case "ci":
+		if err := checkOutputGroupUnset(o); err != nil {
+			return nil, err
+		}
+		return NewCI(os.Getenv), nil
+	case "progress":
+		if err := checkOutputGroupUnset(o); err != nil {
+			return nil, err
+		}
+		// The live view can only be drawn to a terminal
+		if _, ok := logger.Stdout.(*os.File); !ok || !term.IsTerminal() {
+			return Interleaved{}, nil
+		}
+		return NewProgress(logger.Stdout, logger), nil
+	case "files":
+		if err := checkOutputGroupUnset(o); err != nil {
+			return nil, err
+		}
+		return NewFiles(cmp.Or(o.Files.Dir, DefaultFilesDir), logger), nil
 	default:
 		return nil, fmt.Errorf(`task: output style %q not recognized`, o.Name)
 	}
@@ -43,5 +72,11 @@ func checkOutputGroupUnset(o *ast.Output) error {
 	if o.Group.IsSet() {
 		return fmt.Errorf("task: output style %q does not support the group begin/end parameter", o.Name)
 	}
+	if o.Name != "prefixed" && o.Prefixed.IsSet() {
+		return fmt.Errorf("task: output style %q does not support the prefixed color stderr parameter", o.Name)
+	}
+	if o.Name != "files" && o.Files.IsSet() {
+		return fmt.Errorf("task: output style %q does not support the files dir parameter", o.Name)
+	}
 	return nil
 }
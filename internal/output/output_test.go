@@ -5,7 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/stretchr/testify/assert"
@@ -33,21 +37,22 @@ func TestInterleaved(t *testing.T) {
 func TestGroup(t *testing.T) {
 	t.Parallel()
 
-	var b bytes.Buffer
+	var b, e bytes.Buffer
 	var o output.Output = output.Group{}
-	stdOut, stdErr, cleanup := o.WrapWriter(&b, io.Discard, "", nil)
+	stdOut, stdErr, cleanup := o.WrapWriter(&b, &e, "", nil)
 
 	fmt.Fprintln(stdOut, "out\nout")
 	assert.Equal(t, "", b.String())
 	fmt.Fprintln(stdErr, "err\nerr")
-	assert.Equal(t, "", b.String())
+	assert.Equal(t, "", e.String())
 	fmt.Fprintln(stdOut, "out")
 	assert.Equal(t, "", b.String())
 	fmt.Fprintln(stdErr, "err")
-	assert.Equal(t, "", b.String())
+	assert.Equal(t, "", e.String())
 
 	require.NoError(t, cleanup(nil))
-	assert.Equal(t, "out\nout\nerr\nerr\nout\nerr\n", b.String())
+	assert.Equal(t, "out\nout\nout\n", b.String())
+	assert.Equal(t, "err\nerr\nerr\n", e.String())
 }
 
 func TestGroupWithBeginEnd(t *testing.T) {
@@ -96,7 +101,7 @@ func TestGroupErrorOnlySwallowsOutputOnNoError(t *testing.T) {
 	var o output.Output = output.Group{
 		ErrorOnly: true,
 	}
-	stdOut, stdErr, cleanup := o.WrapWriter(&b, io.Discard, "", nil)
+	stdOut, stdErr, cleanup := o.WrapWriter(&b, &b, "", nil)
 
 	_, _ = fmt.Fprintln(stdOut, "std-out")
 	_, _ = fmt.Fprintln(stdErr, "std-err")
@@ -112,7 +117,7 @@ func TestGroupErrorOnlyShowsOutputOnError(t *testing.T) {
 	var o output.Output = output.Group{
 		ErrorOnly: true,
 	}
-	stdOut, stdErr, cleanup := o.WrapWriter(&b, io.Discard, "", nil)
+	stdOut, stdErr, cleanup := o.WrapWriter(&b, &b, "", nil)
 
 	_, _ = fmt.Fprintln(stdOut, "std-out")
 	_, _ = fmt.Fprintln(stdErr, "std-err")
@@ -190,3 +195,290 @@ func TestPrefixedWithColor(t *testing.T) {
 		}
 	})
 }
+
+func TestPrefixedStderr(t *testing.T) {
+	t.Parallel()
+
+	l := &logger.Logger{Color: true}
+	o := output.NewPrefixed(l)
+	o.ColorStderr = true
+
+	var outBuff, errBuff bytes.Buffer
+	stdOut, stdErr, cleanup := o.WrapWriter(&outBuff, &errBuff, "prefix", nil)
+
+	fmt.Fprint(stdOut, "o")
+	fmt.Fprintln(stdErr, "err")
+	fmt.Fprintln(stdOut, "ut")
+	require.NoError(t, cleanup(nil))
+
+	var prefix, line bytes.Buffer
+	l.FOutf(&prefix, output.PrefixColorSequence[0], "prefix")
+	l.FOutf(&line, logger.Red, "err")
+	assert.Equal(t, fmt.Sprintf("[%s] out\n", prefix.String()), outBuff.String())
+	assert.Equal(t, fmt.Sprintf("[%s] %s\n", prefix.String(), line.String()), errBuff.String())
+}
+
+func TestGroupColorStderr(t *testing.T) {
+	t.Parallel()
+
+	l := &logger.Logger{Color: true}
+	var o output.Output = output.Group{ColorStderr: true, Logger: l}
+
+	var b bytes.Buffer
+	stdOut, stdErr, cleanup := o.WrapWriter(&b, &b, "", nil)
+
+	fmt.Fprintln(stdErr, "err")
+	fmt.Fprintln(stdOut, "out")
+	require.NoError(t, cleanup(nil))
+
+	var line bytes.Buffer
+	l.FOutf(&line, logger.Red, "err\n")
+	assert.Equal(t, "out\n"+line.String(), b.String())
+}
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+
+	l := &logger.Logger{Color: false}
+	var b, teeOut, teeErr bytes.Buffer
+	var o output.Output = output.Tee{
+		Output: output.NewPrefixed(l),
+		Stdout: &teeOut,
+		Stderr: &teeErr,
+	}
+	stdOut, stdErr, cleanup := o.WrapWriter(&b, &b, "prefix", nil)
+
+	fmt.Fprintln(stdOut, "out")
+	fmt.Fprintln(stdErr, "err")
+	require.NoError(t, cleanup(nil))
+	// Other tests enable colors, which makes even uncolored prefixes reset it
+	var prefix bytes.Buffer
+	l.FOutf(&prefix, output.PrefixColorSequence[0], "prefix")
+	assert.Equal(t, fmt.Sprintf("[%s] out\n[%s] err\n", prefix.String(), prefix.String()), b.String())
+	assert.Equal(t, "out\n", teeOut.String())
+	assert.Equal(t, "err\n", teeErr.String())
+}
+
+func TestTimestamped(t *testing.T) {
+	t.Parallel()
+
+	t.Run("wall", func(t *testing.T) {
+		t.Parallel()
+
+		var b bytes.Buffer
+		var o output.Output = output.Timestamped{
+			Output: output.Interleaved{},
+			Format: output.TimestampsWall,
+		}
+		stdOut, stdErr, cleanup := o.WrapWriter(&b, &b, "", nil)
+
+		fmt.Fprint(stdOut, "foo\nb")
+		fmt.Fprintln(stdOut, "ar")
+		fmt.Fprintln(stdErr, "err")
+		require.NoError(t, cleanup(nil))
+		assert.Regexp(t, `^\d\d:\d\d:\d\d\.\d{3} foo\n\d\d:\d\d:\d\d\.\d{3} bar\n\d\d:\d\d:\d\d\.\d{3} err\n$`, b.String())
+	})
+
+	t.Run("elapsed", func(t *testing.T) {
+		t.Parallel()
+
+		var b bytes.Buffer
+		var o output.Output = output.Timestamped{
+			Output: output.Interleaved{},
+			Format: output.TimestampsElapsed,
+			Start:  time.Now().Add(-90 * time.Second),
+		}
+		stdOut, _, cleanup := o.WrapWriter(&b, io.Discard, "", nil)
+
+		fmt.Fprint(stdOut, "foo\nb")
+		fmt.Fprintln(stdOut, "ar")
+		require.NoError(t, cleanup(nil))
+		assert.Regexp(t, `^   90\.\d{3}s foo\n   90\.\d{3}s bar\n$`, b.String())
+	})
+}
+
+func TestCI(t *testing.T) {
+	t.Parallel()
+
+	task := &ast.Task{
+		Task:     "build",
+		Location: &ast.Location{Taskfile: "/work/Taskfile.yml", Line: 4, Column: 3},
+	}
+	// Both streams are written to the same buffer, like they are to the log
+	// of a CI job
+	run := func(o *output.CI, err error) string {
+		var b bytes.Buffer
+		taskOut, taskErr, closeTask := o.WrapTask(&b, &b, task, nil)
+		stdOut, stdErr, cleanup := o.WrapWriter(taskOut, taskErr, "build", nil)
+		fmt.Fprintln(stdOut, "out")
+		fmt.Fprint(stdErr, "err")
+		require.NoError(t, cleanup(err))
+		assert.Empty(t, b.String())
+		require.NoError(t, closeTask(err))
+		return b.String()
+	}
+	env := func(env map[string]string) func(string) string {
+		return func(key string) string { return env[key] }
+	}
+
+	t.Run("github actions", func(t *testing.T) {
+		t.Parallel()
+
+		o := output.NewCI(env(map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_WORKSPACE": "/work"}))
+		assert.Equal(t, output.CIGitHubActions, o.System)
+		assert.Equal(t, "::group::build\nout\nerr\n::endgroup::\n", run(o, nil))
+
+		var b bytes.Buffer
+		o.Annotate(&b, task, "exit 1", errors.New("exit status 1"))
+		assert.Equal(t, "::error title=task%3A build,file=Taskfile.yml,line=4,col=3::Command \"exit 1\" failed: exit status 1\n", b.String())
+	})
+
+	t.Run("gitlab", func(t *testing.T) {
+		t.Parallel()
+
+		o := output.NewCI(env(map[string]string{"GITLAB_CI": "true"}))
+		assert.Equal(t, output.CIGitLab, o.System)
+		assert.Regexp(t, `^\x1b\[0Ksection_start:\d+:task_1_build\[collapsed=true\]\r\x1b\[0Kbuild\nout\nerr\n\x1b\[0Ksection_end:\d+:task_1_build\r\x1b\[0K\n$`, run(o, nil))
+		assert.Contains(t, run(o, errors.New("failed")), "task_2_build[collapsed=false]")
+
+		var b bytes.Buffer
+		o.Annotate(&b, task, "exit 1", errors.New("exit status 1"))
+		assert.Empty(t, b.String())
+	})
+
+	t.Run("buildkite", func(t *testing.T) {
+		t.Parallel()
+
+		o := output.NewCI(env(map[string]string{"BUILDKITE": "true"}))
+		assert.Equal(t, output.CIBuildkite, o.System)
+		assert.Equal(t, "--- build\nout\nerr\n", run(o, nil))
+		assert.Equal(t, "+++ build\nout\nerr\n", run(o, errors.New("failed")))
+	})
+
+	t.Run("no ci", func(t *testing.T) {
+		t.Parallel()
+
+		o := output.NewCI(env(nil))
+		assert.Empty(t, o.System)
+		assert.Equal(t, "out\nerr\n", run(o, nil))
+	})
+
+	t.Run("separate streams", func(t *testing.T) {
+		t.Parallel()
+
+		o := output.NewCI(env(map[string]string{"GITHUB_ACTIONS": "true"}))
+		var stdOutBuff, stdErrBuff bytes.Buffer
+		taskOut, taskErr, closeTask := o.WrapTask(&stdOutBuff, &stdErrBuff, task, nil)
+		fmt.Fprintln(taskOut, "out")
+		fmt.Fprintln(taskErr, "err")
+		fmt.Fprintln(taskOut, "more out")
+		assert.Empty(t, stdOutBuff.String())
+		assert.Empty(t, stdErrBuff.String())
+		require.NoError(t, closeTask(nil))
+		assert.Equal(t, "::group::build\nout\nmore out\n::endgroup::\n", stdOutBuff.String())
+		assert.Equal(t, "err\n", stdErrBuff.String())
+	})
+}
+
+func TestFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	var b bytes.Buffer
+	o := output.NewFiles(dir, &logger.Logger{Color: false})
+	task := &ast.Task{Task: "ns:build"}
+	vars := ast.NewVars(&ast.VarElement{Key: "TARGET", Value: ast.Var{Value: "linux/amd64"}})
+
+	run := func(err error, lines ...string) {
+		taskOut, taskErr, closeTask := o.WrapTask(&b, &b, task, vars)
+		stdOut, stdErr, cleanup := o.WrapWriter(taskOut, taskErr, "build", nil)
+		fmt.Fprintln(stdOut, lines[0])
+		fmt.Fprintln(stdErr, lines[1])
+		require.NoError(t, cleanup(err))
+		require.NoError(t, closeTask(err))
+	}
+
+	run(nil, "out", "err")
+	assert.Empty(t, b.String())
+	run(errors.New("failed"), "out again", "err again")
+
+	path := filepath.Join(dir, output.LogFileName(task, vars))
+	assert.Regexp(t, `^ns_build_TARGET=linux_amd64_[0-9a-f]{8}\.log$`, filepath.Base(path))
+	assert.Equal(t, fmt.Sprintf("task: [ns:build] output written to %s\n", path), b.String())
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "out\nerr\nout again\nerr again\n", string(content))
+
+	// The log files of a previous run are truncated
+	o = output.NewFiles(dir, &logger.Logger{Color: false})
+	run(nil, "new", "run")
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new\nrun\n", string(content))
+}
+
+func TestLogFileName(t *testing.T) {
+	t.Parallel()
+
+	task := &ast.Task{Task: "build"}
+	assert.Equal(t, "build.log", output.LogFileName(task, nil))
+	assert.Equal(t, "build_MATCH=a_b.log", output.LogFileName(task, ast.NewVars(
+		&ast.VarElement{Key: "MATCH", Value: ast.Var{Value: []string{"a", "b"}}},
+	)))
+	assert.Equal(t, "build.log", output.LogFileName(task, ast.NewVars(
+		&ast.VarElement{Key: "MATCH", Value: ast.Var{Value: []string{}}},
+	)))
+
+	long := output.LogFileName(task, ast.NewVars(
+		&ast.VarElement{Key: "TEXT", Value: ast.Var{Value: strings.Repeat("a", 500)}},
+	))
+	assert.Len(t, long, 128+len(".log"))
+
+	// Tasks whose names only differ in characters that aren't allowed in file
+	// names get different log files
+	assert.Equal(t, "a_b.log", output.LogFileName(&ast.Task{Task: "a_b"}, nil))
+	assert.Regexp(t, `^a_b_[0-9a-f]{8}\.log$`, output.LogFileName(&ast.Task{Task: "a:b"}, nil))
+	assert.NotEqual(t,
+		output.LogFileName(&ast.Task{Task: "a:b"}, nil),
+		output.LogFileName(&ast.Task{Task: "a/b"}, nil),
+	)
+}
+
+func TestProgress(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	o := output.NewProgress(&b, &logger.Logger{Color: false})
+	o.Interval = time.Hour
+	log := o.Writer(&b)
+
+	build, buildErr, closeBuild := o.WrapTask(&b, &b, &ast.Task{Task: "build"}, nil)
+	test, _, closeTest := o.WrapTask(&b, &b, &ast.Task{Task: "test"}, nil)
+	dequeue := o.Queue(&ast.Task{Task: "lint"})
+
+	fmt.Fprintln(build, "compiling")
+	fmt.Fprintln(test, "\x1b[32mrunning\x1b[0m")
+	fmt.Fprintln(log, "task: [build] go build")
+	assert.Contains(t, b.String(), "task: [build] go build\n")
+	assert.Regexp(t, `build \([^)]+\) compiling\n`, b.String())
+	assert.Regexp(t, `test \([^)]+\) running\n`, b.String())
+	assert.Contains(t, b.String(), "  1 waiting: lint\n")
+
+	// The output of a task is only printed if it fails
+	dequeue()
+	fmt.Fprint(buildErr, "build failed")
+	require.NoError(t, closeTest(nil))
+	require.NoError(t, closeBuild(errors.New("failed")))
+	assert.Regexp(t, `✓ test \([^)]+\)\n`, b.String())
+	assert.Regexp(t, `✗ build \([^)]+\)\ncompiling\nbuild failed\n$`, b.String())
+	assert.NotContains(t, b.String(), "✓ build")
+}
+
+func TestProgressWithoutTerminal(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	o, err := output.BuildFor(&ast.Output{Name: "progress"}, &logger.Logger{Stdout: &b, Stderr: &b})
+	require.NoError(t, err)
+	assert.Equal(t, output.Interleaved{}, o)
+}
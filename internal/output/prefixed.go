@@ -2,6 +2,7 @@ package output
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -12,10 +13,12 @@ import (
 )
 
 type Prefixed struct {
-	logger  *logger.Logger
-	seen    map[string]uint
-	counter *uint
-	mutex   sync.Mutex
+	// ColorStderr colors the lines that commands write to stderr red.
+	ColorStderr bool
+	logger      *logger.Logger
+	seen        map[string]uint
+	counter     *uint
+	mutex       sync.Mutex
 }
 
 func NewPrefixed(logger *logger.Logger) *Prefixed {
@@ -28,15 +31,21 @@ func NewPrefixed(logger *logger.Logger) *Prefixed {
 	}
 }
 
-func (p *Prefixed) WrapWriter(stdOut, _ io.Writer, prefix string, _ *templater.Cache) (io.Writer, io.Writer, CloseFunc) {
-	pw := &prefixWriter{writer: stdOut, prefix: prefix, prefixed: p}
-	return pw, pw, func(error) error { return pw.close() }
+func (p *Prefixed) WrapWriter(stdOut, stdErr io.Writer, prefix string, _ *templater.Cache) (io.Writer, io.Writer, CloseFunc) {
+	// Each stream is buffered on its own so that a partial line written to
+	// one of them doesn't end up in the middle of a line of the other
+	outWriter := &prefixWriter{writer: stdOut, prefix: prefix, prefixed: p}
+	errWriter := &prefixWriter{writer: stdErr, prefix: prefix, prefixed: p, stderr: true}
+	return outWriter, errWriter, func(error) error {
+		return errors.Join(outWriter.close(), errWriter.close())
+	}
 }
 
 type prefixWriter struct {
 	writer   io.Writer
 	prefixed *Prefixed
 	prefix   string
+	stderr   bool
 	buff     bytes.Buffer
 }
 
@@ -110,6 +119,11 @@ func (pw *prefixWriter) writeLine(line string) error {
 		return nil
 	}
 
+	if pw.stderr && pw.prefixed.ColorStderr {
+		pw.prefixed.logger.FOutf(pw.writer, logger.Red, strings.TrimSuffix(line, "\n"))
+		line = "\n"
+	}
+
 	_, err := fmt.Fprint(pw.writer, line)
 	return err
 }
@@ -0,0 +1,318 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// A Queuer is an [Output] that shows the tasks that are waiting for a free
+// slot when the number of concurrent tasks is limited.
+type Queuer interface {
+	// Queue marks the given task as waiting until the returned function is
+	// called.
+	Queue(t *ast.Task) func()
+}
+
+// A Pauser is an [Output] that has to stop drawing to the terminal while a
+// task needs it, like when it's interactive or prompts the user.
+type Pauser interface {
+	// Pause stops drawing until the returned function is called.
+	Pause() func()
+}
+
+// DefaultProgressInterval is how often the progress of running tasks is
+// redrawn.
+const DefaultProgressInterval = 100 * time.Millisecond
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Progress is an [Output] that shows a live view of the tasks that are running,
+// with how long they have been running and the last line they printed, and of
+// the tasks that are waiting for a free slot. The output of a task is only
+// printed if it fails. Other lines, like the commands that Task runs, are
+// printed above the live view when written through [Progress.Writer].
+type Progress struct {
+	Interval time.Duration
+
+	writer  io.Writer
+	logger  *logger.Logger
+	width   int
+	mutex   sync.Mutex
+	running []*progressTask
+	queued  []string
+	// lines is the number of lines of the live view currently on the screen
+	lines   int
+	frame   int
+	paused  int
+	partial bool
+	ticker  *time.Ticker
+	done    chan struct{}
+}
+
+type progressTask struct {
+	name  string
+	start time.Time
+	buff  bytes.Buffer
+}
+
+// NewProgress creates a new [Progress] that draws to the given terminal.
+func NewProgress(w io.Writer, logger *logger.Logger) *Progress {
+	p := &Progress{
+		Interval: DefaultProgressInterval,
+		writer:   w,
+		logger:   logger,
+	}
+	if f, ok := w.(*os.File); ok {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil {
+			p.width = width
+		}
+	}
+	return p
+}
+
+// WrapWriter doesn't wrap the output of commands, since the output of the
+// whole task is already wrapped by [Progress.WrapTask].
+func (p *Progress) WrapWriter(stdOut, stdErr io.Writer, _ string, _ *templater.Cache) (io.Writer, io.Writer, CloseFunc) {
+	return stdOut, stdErr, func(error) error { return nil }
+}
+
+func (p *Progress) WrapTask(_, _ io.Writer, t *ast.Task, _ *ast.Vars) (io.Writer, io.Writer, CloseFunc) {
+	pt := &progressTask{name: t.Name(), start: time.Now()}
+
+	p.mutex.Lock()
+	p.running = append(p.running, pt)
+	p.start()
+	p.mutex.Unlock()
+
+	w := &progressTaskWriter{progress: p, task: pt}
+	return w, w, func(err error) error {
+		return p.finish(pt, err)
+	}
+}
+
+func (p *Progress) Queue(t *ast.Task) func() {
+	name := t.Name()
+
+	p.mutex.Lock()
+	p.queued = append(p.queued, name)
+	p.start()
+	p.mutex.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mutex.Lock()
+			defer p.mutex.Unlock()
+			if i := slices.Index(p.queued, name); i >= 0 {
+				p.queued = append(p.queued[:i], p.queued[i+1:]...)
+			}
+			p.redraw()
+		})
+	}
+}
+
+func (p *Progress) Pause() func() {
+	p.mutex.Lock()
+	p.paused++
+	p.clear()
+	p.mutex.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mutex.Lock()
+			defer p.mutex.Unlock()
+			p.paused--
+			p.draw()
+		})
+	}
+}
+
+// Writer returns an [io.Writer] that writes to w above the live view, so that
+// lines printed while tasks are running don't get mixed up with it.
+func (p *Progress) Writer(w io.Writer) io.Writer {
+	return &progressLogWriter{progress: p, writer: w}
+}
+
+// finish removes the given task from the live view and prints whether it
+// succeeded above it, together with the output of the task if it failed.
+func (p *Progress) finish(pt *progressTask, err error) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if i := slices.Index(p.running, pt); i >= 0 {
+		p.running = append(p.running[:i], p.running[i+1:]...)
+	}
+	p.clear()
+	elapsed := time.Since(pt.start).Round(time.Millisecond)
+	var writeErr error
+	if err == nil {
+		p.logger.FOutf(p.writer, logger.Green, "✓ %s (%s)\n", pt.name, elapsed)
+	} else {
+		p.logger.FOutf(p.writer, logger.Red, "✗ %s (%s)\n", pt.name, elapsed)
+		output := pt.buff.Bytes()
+		if len(output) > 0 && !bytes.HasSuffix(output, []byte("\n")) {
+			output = append(output, '\n')
+		}
+		_, writeErr = p.writer.Write(output)
+	}
+	p.stopIfIdle()
+	p.draw()
+	return writeErr
+}
+
+// start starts redrawing the live view periodically, if it isn't already.
+func (p *Progress) start() {
+	if p.ticker != nil {
+		p.redraw()
+		return
+	}
+	p.ticker = time.NewTicker(p.Interval)
+	p.done = make(chan struct{})
+	go func(ticker *time.Ticker, done chan struct{}) {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				p.mutex.Lock()
+				p.frame++
+				p.redraw()
+				p.mutex.Unlock()
+			}
+		}
+	}(p.ticker, p.done)
+	p.redraw()
+}
+
+// stopIfIdle stops redrawing the live view once there are no running or queued
+// tasks left, so that nothing is drawn after the run has finished.
+func (p *Progress) stopIfIdle() {
+	if len(p.running) > 0 || len(p.queued) > 0 || p.ticker == nil {
+		return
+	}
+	p.ticker.Stop()
+	close(p.done)
+	p.ticker = nil
+	p.clear()
+}
+
+func (p *Progress) redraw() {
+	p.clear()
+	p.draw()
+}
+
+// clear removes the live view from the screen.
+func (p *Progress) clear() {
+	if p.lines == 0 {
+		return
+	}
+	fmt.Fprintf(p.writer, "\r\x1b[%dA\x1b[J", p.lines)
+	p.lines = 0
+}
+
+// draw draws the live view below the cursor.
+func (p *Progress) draw() {
+	if p.paused > 0 || p.partial || p.ticker == nil {
+		return
+	}
+	var b bytes.Buffer
+	spinner := spinnerFrames[p.frame%len(spinnerFrames)]
+	for _, pt := range p.running {
+		elapsed := time.Since(pt.start).Truncate(100 * time.Millisecond)
+		line := strings.TrimSpace(fmt.Sprintf("%s %s (%s) %s", spinner, pt.name, elapsed, lastLine(pt.buff.Bytes())))
+		b.WriteString(p.truncate(line))
+		b.WriteString("\n")
+		p.lines++
+	}
+	if len(p.queued) > 0 {
+		line := fmt.Sprintf("  %d waiting: %s", len(p.queued), strings.Join(p.queued, ", "))
+		b.WriteString(p.truncate(line))
+		b.WriteString("\n")
+		p.lines++
+	}
+	_, _ = p.writer.Write(b.Bytes())
+}
+
+// truncate cuts the given line to the width of the terminal, so that every line
+// of the live view takes a single line on the screen and can be cleared.
+func (p *Progress) truncate(line string) string {
+	if p.width <= 0 {
+		return line
+	}
+	runes := []rune(line)
+	if len(runes) < p.width {
+		return line
+	}
+	return string(runes[:p.width-1])
+}
+
+var ansiRegexp = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// lastLine returns the last line of the given output that isn't empty, without
+// any colors or carriage returns used to redraw progress bars.
+func lastLine(output []byte) string {
+	output = bytes.TrimRight(output, "\r\n")
+	if i := bytes.LastIndexByte(output, '\n'); i >= 0 {
+		output = output[i+1:]
+	}
+	if i := bytes.LastIndexByte(output, '\r'); i >= 0 {
+		output = output[i+1:]
+	}
+	line := ansiRegexp.ReplaceAllString(string(output), "")
+	return strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		}
+		if r < ' ' {
+			return -1
+		}
+		return r
+	}, line)
+}
+
+type progressTaskWriter struct {
+	progress *Progress
+	task     *progressTask
+}
+
+func (w *progressTaskWriter) Write(b []byte) (int, error) {
+	w.progress.mutex.Lock()
+	defer w.progress.mutex.Unlock()
+	return w.task.buff.Write(b)
+}
+
+type progressLogWriter struct {
+	progress *Progress
+	writer   io.Writer
+}
+
+func (w *progressLogWriter) Write(b []byte) (int, error) {
+	p := w.progress
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	// Colors are set and reset in their own writes, which don't change what
+	// is on the screen
+	if len(ansiRegexp.ReplaceAll(b, nil)) == 0 {
+		return w.writer.Write(b)
+	}
+	p.clear()
+	n, err := w.writer.Write(b)
+	// The live view is only drawn again once the line is complete
+	p.partial = len(b) > 0 && b[len(b)-1] != '\n'
+	p.draw()
+	return n, err
+}
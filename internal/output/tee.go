@@ -0,0 +1,20 @@
+package output
+
+import (
+	"io"
+
+	"github.com/go-task/task/v3/internal/templater"
+)
+
+// Tee wraps another [Output] and also copies everything the commands write to
+// Stdout and Stderr, before it is prefixed or grouped by the wrapped [Output].
+type Tee struct {
+	Output Output
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+func (t Tee) WrapWriter(stdOut, stdErr io.Writer, prefix string, cache *templater.Cache) (io.Writer, io.Writer, CloseFunc) {
+	stdOut, stdErr, closer := t.Output.WrapWriter(stdOut, stdErr, prefix, cache)
+	return io.MultiWriter(stdOut, t.Stdout), io.MultiWriter(stdErr, t.Stderr), closer
+}
@@ -0,0 +1,75 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-task/task/v3/internal/templater"
+)
+
+// Formats of the timestamps that [Timestamped] prepends to lines
+const (
+	// TimestampsWall is the time of day the line was printed at.
+	TimestampsWall = "wall"
+	// TimestampsElapsed is the time since the run started.
+	TimestampsElapsed = "elapsed"
+)
+
+// TimestampFormats are the formats that [Timestamped] supports.
+var TimestampFormats = []string{TimestampsWall, TimestampsElapsed}
+
+// TimestampStyles are the output styles that [Timestamped] can wrap. Other
+// styles print the output of commands after they finish, so the timestamps
+// would only tell when the output was printed.
+var TimestampStyles = []string{"", "interleaved", "prefixed"}
+
+// Timestamped wraps another [Output] and prepends a timestamp to every line
+// that it prints, before the prefix of the line if there is one.
+type Timestamped struct {
+	Output Output
+	Format string
+	// Start is the time that elapsed timestamps are relative to.
+	Start time.Time
+}
+
+func (t Timestamped) WrapWriter(stdOut, stdErr io.Writer, prefix string, cache *templater.Cache) (io.Writer, io.Writer, CloseFunc) {
+	return t.Output.WrapWriter(
+		&timestampWriter{writer: stdOut, timestamped: t, lineStart: true},
+		&timestampWriter{writer: stdErr, timestamped: t, lineStart: true},
+		prefix,
+		cache,
+	)
+}
+
+func (t Timestamped) timestamp(now time.Time) string {
+	if t.Format == TimestampsElapsed {
+		return fmt.Sprintf("%9.3fs ", now.Sub(t.Start).Seconds())
+	}
+	return now.Format("15:04:05.000 ")
+}
+
+// timestampWriter prepends a timestamp to the first write of every line, which
+// may be split in several writes by the wrapped [Output].
+type timestampWriter struct {
+	writer      io.Writer
+	timestamped Timestamped
+	lineStart   bool
+}
+
+func (tw *timestampWriter) Write(p []byte) (int, error) {
+	timestamp := tw.timestamped.timestamp(time.Now())
+	b := make([]byte, 0, len(p)+len(timestamp))
+	for _, c := range p {
+		if tw.lineStart {
+			b = append(b, timestamp...)
+			tw.lineStart = false
+		}
+		b = append(b, c)
+		tw.lineStart = c == '\n'
+	}
+	if _, err := tw.writer.Write(b); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,216 @@
+// Package report collects the outcome of every task of a run from its events,
+// so that they can be summarized once the run has finished.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ladicle/tabwriter"
+
+	"github.com/go-task/task/v3/internal/events"
+)
+
+// Formats that a report can be written in.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formats lists every supported report format.
+var Formats = []string{FormatText, FormatJSON}
+
+// Outcome is what happened to a task during a run.
+type Outcome string
+
+const (
+	OutcomeRan          Outcome = "ran"
+	OutcomeUpToDate     Outcome = "up-to-date"
+	OutcomeRestored     Outcome = "restored"
+	OutcomeDeduplicated Outcome = "deduplicated"
+	OutcomeSkipped      Outcome = "skipped"
+	OutcomeFailed       Outcome = "failed"
+)
+
+// outcomes lists every outcome in the order they are summarized.
+var outcomes = []Outcome{
+	OutcomeRan,
+	OutcomeUpToDate,
+	OutcomeRestored,
+	OutcomeDeduplicated,
+	OutcomeSkipped,
+	OutcomeFailed,
+}
+
+type (
+	// A Recorder collects the outcome of every task from the events of a
+	// run. It is safe for concurrent use. All methods are no-ops on a nil
+	// Recorder, so callers don't need to check whether the report is enabled.
+	Recorder struct {
+		mutex sync.Mutex
+		start time.Time
+		tasks []*Task
+	}
+	// A Task is the outcome of a single call to a task.
+	Task struct {
+		Name     string        `json:"task"`
+		Outcome  Outcome       `json:"outcome"`
+		Duration time.Duration `json:"-"`
+		ExitCode *int          `json:"exit_code,omitempty"`
+		Error    string        `json:"error,omitempty"`
+		Message  string        `json:"message,omitempty"`
+	}
+)
+
+// New creates a new [Recorder].
+func New() *Recorder {
+	return &Recorder{start: time.Now()}
+}
+
+// Handle records the outcome of the task the given event is about, if the event
+// is the last one emitted for a call to a task. It is meant to be subscribed to
+// an [events.Stream].
+func (r *Recorder) Handle(ev events.Event) {
+	if r == nil {
+		return
+	}
+	var outcome Outcome
+	switch ev.Type {
+	case events.TaskFinished:
+		outcome = OutcomeRan
+	case events.TaskUpToDate:
+		outcome = OutcomeUpToDate
+	case events.TaskRestored:
+		outcome = OutcomeRestored
+	case events.TaskDeduplicated:
+		outcome = OutcomeDeduplicated
+	case events.TaskSkipped:
+		outcome = OutcomeSkipped
+	case events.TaskFailed:
+		outcome = OutcomeFailed
+	default:
+		return
+	}
+	t := &Task{
+		Name:     ev.Task,
+		Outcome:  outcome,
+		ExitCode: ev.ExitCode,
+		Error:    ev.Error,
+		Message:  ev.Message,
+	}
+	if ev.Duration != nil {
+		t.Duration = time.Duration(*ev.Duration * float64(time.Millisecond))
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.tasks = append(r.tasks, t)
+}
+
+// Tasks returns the outcome of every task, in the order they finished.
+func (r *Recorder) Tasks() []*Task {
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	tasks := make([]*Task, len(r.tasks))
+	copy(tasks, r.tasks)
+	return tasks
+}
+
+// Counts returns the number of tasks with each outcome.
+func (r *Recorder) Counts() map[Outcome]int {
+	counts := make(map[Outcome]int, len(outcomes))
+	for _, t := range r.Tasks() {
+		counts[t.Outcome]++
+	}
+	return counts
+}
+
+// Write writes the report to w in the given format.
+func (r *Recorder) Write(w io.Writer, format string) error {
+	if r == nil {
+		return nil
+	}
+	switch format {
+	case FormatText, "":
+		return r.writeText(w)
+	case FormatJSON:
+		return r.writeJSON(w)
+	default:
+		return fmt.Errorf("task: unknown report format %q", format)
+	}
+}
+
+func (r *Recorder) writeText(w io.Writer) error {
+	tasks := r.Tasks()
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "TASK\tOUTCOME\tDURATION\tDETAILS")
+	for _, t := range tasks {
+		duration := "-"
+		if t.Duration > 0 {
+			duration = round(t.Duration).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.Name, t.Outcome, duration, t.details())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	counts := r.Counts()
+	var summary []string
+	for _, outcome := range outcomes {
+		if counts[outcome] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[outcome], outcome))
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d tasks in %s: %s\n", len(tasks), round(time.Since(r.start)), strings.Join(summary, ", "))
+	return err
+}
+
+func (t *Task) details() string {
+	switch {
+	case t.ExitCode != nil:
+		return fmt.Sprintf("exit code %d", *t.ExitCode)
+	case t.Error != "":
+		return t.Error
+	default:
+		return t.Message
+	}
+}
+
+func (r *Recorder) writeJSON(w io.Writer) error {
+	type jsonTask struct {
+		*Task
+		Duration float64 `json:"duration_ms"`
+	}
+	tasks := r.Tasks()
+	output := struct {
+		Tasks    []jsonTask      `json:"tasks"`
+		Counts   map[Outcome]int `json:"counts"`
+		Duration float64         `json:"duration_ms"`
+	}{
+		Tasks:    make([]jsonTask, len(tasks)),
+		Counts:   r.Counts(),
+		Duration: milliseconds(time.Since(r.start)),
+	}
+	for i, t := range tasks {
+		output.Tasks[i] = jsonTask{Task: t, Duration: milliseconds(t.Duration)}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Millisecond)
+}
@@ -0,0 +1,71 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/internal/events"
+	"github.com/go-task/task/v3/internal/report"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	duration := 12.5
+	exitCode := 3
+	r := report.New()
+	r.Handle(events.Event{Type: events.TaskStarted, Task: "build"})
+	r.Handle(events.Event{Type: events.TaskFinished, Task: "build", Duration: &duration})
+	r.Handle(events.Event{Type: events.TaskDeduplicated, Task: "build"})
+	r.Handle(events.Event{Type: events.TaskUpToDate, Task: "generate", Duration: &duration})
+	r.Handle(events.Event{Type: events.TaskSkipped, Task: "windows", Message: "not for current platform"})
+	r.Handle(events.Event{Type: events.TaskFailed, Task: "test", Duration: &duration, ExitCode: &exitCode, Error: "exit status 3"})
+
+	tasks := r.Tasks()
+	require.Len(t, tasks, 5)
+	assert.Equal(t, report.OutcomeRan, tasks[0].Outcome)
+	assert.Equal(t, report.OutcomeDeduplicated, tasks[1].Outcome)
+	assert.Equal(t, report.OutcomeUpToDate, tasks[2].Outcome)
+	assert.Equal(t, report.OutcomeSkipped, tasks[3].Outcome)
+	assert.Equal(t, report.OutcomeFailed, tasks[4].Outcome)
+
+	var text bytes.Buffer
+	require.NoError(t, r.Write(&text, report.FormatText))
+	assert.Contains(t, text.String(), "exit code 3")
+	assert.Contains(t, text.String(), "5 tasks in")
+	assert.Contains(t, text.String(), "1 ran, 1 up-to-date, 1 deduplicated, 1 skipped, 1 failed")
+
+	var output struct {
+		Tasks []struct {
+			Task     string  `json:"task"`
+			Outcome  string  `json:"outcome"`
+			Duration float64 `json:"duration_ms"`
+			ExitCode *int    `json:"exit_code"`
+		} `json:"tasks"`
+		Counts map[string]int `json:"counts"`
+	}
+	var buff bytes.Buffer
+	require.NoError(t, r.Write(&buff, report.FormatJSON))
+	require.NoError(t, json.Unmarshal(buff.Bytes(), &output))
+	require.Len(t, output.Tasks, 5)
+	assert.Equal(t, "test", output.Tasks[4].Task)
+	assert.Equal(t, "failed", output.Tasks[4].Outcome)
+	assert.InDelta(t, 12.5, output.Tasks[4].Duration, 0.001)
+	assert.Equal(t, &exitCode, output.Tasks[4].ExitCode)
+	assert.Equal(t, 1, output.Counts["failed"])
+
+	require.Error(t, r.Write(&buff, "xml"))
+}
+
+func TestNilRecorder(t *testing.T) {
+	t.Parallel()
+
+	var r *report.Recorder
+	r.Handle(events.Event{Type: events.TaskFinished, Task: "build"})
+	assert.Empty(t, r.Tasks())
+	require.NoError(t, r.Write(&bytes.Buffer{}, report.FormatText))
+}
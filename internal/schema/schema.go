@@ -0,0 +1,335 @@
+// Package schema checks YAML documents against the keys allowed by a JSON
+// Schema. Only the keywords that describe which keys an object can have are
+// supported. Types and values are checked when the document is decoded.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sajari/fuzzy"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// A Schema is a subset of a JSON Schema (draft 7).
+	Schema struct {
+		Ref                  string             `json:"$ref"`
+		Type                 types              `json:"type"`
+		Properties           map[string]*Schema `json:"properties"`
+		PatternProperties    map[string]*Schema `json:"patternProperties"`
+		AdditionalProperties *additional        `json:"additionalProperties"`
+		Items                *Schema            `json:"items"`
+		AllOf                []*Schema          `json:"allOf"`
+		AnyOf                []*Schema          `json:"anyOf"`
+		OneOf                []*Schema          `json:"oneOf"`
+		Definitions          map[string]*Schema `json:"definitions"`
+
+		root     *Schema
+		patterns map[string]*regexp.Regexp
+	}
+	// An UnknownKey is a key in a YAML mapping that is not allowed by the
+	// schema.
+	UnknownKey struct {
+		// Node is the node of the key.
+		Node *yaml.Node
+		// Known holds the keys that are allowed in the mapping.
+		Known []string
+	}
+	// types holds the value of the "type" keyword, which can be a single type
+	// or a list of types.
+	types []string
+	// additional holds the value of the "additionalProperties" keyword, which
+	// can be a boolean or a schema.
+	additional struct {
+		allowed bool
+		schema  *Schema
+	}
+)
+
+// Parse parses the given JSON Schema.
+func Parse(b []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("task: unable to parse schema: %w", err)
+	}
+	if err := s.init(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (t *types) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*t = types{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+func (a *additional) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &a.allowed); err == nil {
+		return nil
+	}
+	a.allowed = true
+	return json.Unmarshal(b, &a.schema)
+}
+
+// init links every subschema to the root schema, so that references can be
+// resolved, and compiles the patterns of patternProperties.
+func (s *Schema) init(root *Schema) error {
+	if s == nil {
+		return nil
+	}
+	s.root = root
+	s.patterns = make(map[string]*regexp.Regexp, len(s.PatternProperties))
+	for pattern := range s.PatternProperties {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("task: invalid pattern %q in schema: %w", pattern, err)
+		}
+		s.patterns[pattern] = re
+	}
+
+	children := []*Schema{s.Items}
+	if s.AdditionalProperties != nil {
+		children = append(children, s.AdditionalProperties.schema)
+	}
+	children = append(children, s.AllOf...)
+	children = append(children, s.AnyOf...)
+	children = append(children, s.OneOf...)
+	for _, m := range []map[string]*Schema{s.Properties, s.PatternProperties, s.Definitions} {
+		for _, child := range m {
+			children = append(children, child)
+		}
+	}
+	for _, child := range children {
+		if err := child.init(root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve follows the reference of the schema, if any. Only references to
+// definitions in the same document are supported.
+func (s *Schema) resolve() *Schema {
+	for s != nil && s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/definitions/")
+		if !ok {
+			return nil
+		}
+		s = s.root.Definitions[name]
+	}
+	return s
+}
+
+// accepts returns whether the schema can match a value of the given type.
+func (s *Schema) accepts(typ string) bool {
+	s = s.resolve()
+	if s == nil {
+		return true
+	}
+	if len(s.Type) > 0 && !slices.Contains(s.Type, typ) {
+		return false
+	}
+	for _, sub := range s.AllOf {
+		if !sub.accepts(typ) {
+			return false
+		}
+	}
+	if alternatives := slices.Concat(s.AnyOf, s.OneOf); len(alternatives) > 0 {
+		return slices.ContainsFunc(alternatives, func(sub *Schema) bool {
+			return sub.accepts(typ)
+		})
+	}
+	return true
+}
+
+// property returns whether the schema allows the given key in an object and
+// the schema of its value. A nil schema means that any value is allowed.
+func (s *Schema) property(key string) (*Schema, bool) {
+	s = s.resolve()
+	if s == nil {
+		return nil, true
+	}
+
+	var all []*Schema
+	if child, ok := s.Properties[key]; ok {
+		all = append(all, child)
+	} else if matched := s.matchPatterns(key); len(matched) > 0 {
+		all = append(all, matched...)
+	} else if s.AdditionalProperties != nil {
+		if !s.AdditionalProperties.allowed {
+			return nil, false
+		}
+		all = append(all, s.AdditionalProperties.schema)
+	}
+
+	// Every schema in allOf must allow the key
+	for _, sub := range s.AllOf {
+		child, ok := sub.property(key)
+		if !ok {
+			return nil, false
+		}
+		all = append(all, child)
+	}
+
+	// At least one of the alternatives that can be an object must allow
+	// the key. Alternatives that can't be objects are ignored, since the
+	// decoder reports values of the wrong type.
+	var candidates, matched []*Schema
+	for _, sub := range slices.Concat(s.AnyOf, s.OneOf) {
+		if !sub.accepts("object") {
+			continue
+		}
+		candidates = append(candidates, sub)
+		if child, ok := sub.property(key); ok {
+			matched = append(matched, child)
+		}
+	}
+	if len(candidates) > 0 {
+		if len(matched) == 0 {
+			return nil, false
+		}
+		all = append(all, combine(matched, false))
+	}
+
+	return combine(all, true), true
+}
+
+// items returns the schema of the items of an array. A nil schema means that
+// any item is allowed.
+func (s *Schema) items() *Schema {
+	s = s.resolve()
+	if s == nil {
+		return nil
+	}
+	all := []*Schema{s.Items}
+	for _, sub := range s.AllOf {
+		all = append(all, sub.items())
+	}
+	var alternatives []*Schema
+	for _, sub := range slices.Concat(s.AnyOf, s.OneOf) {
+		if sub.accepts("array") {
+			alternatives = append(alternatives, sub.items())
+		}
+	}
+	if len(alternatives) > 0 {
+		all = append(all, combine(alternatives, false))
+	}
+	return combine(all, true)
+}
+
+// known returns the keys that the schema explicitly allows in an object.
+func (s *Schema) known() []string {
+	s = s.resolve()
+	if s == nil {
+		return nil
+	}
+	var keys []string
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	for _, sub := range slices.Concat(s.AllOf, s.AnyOf, s.OneOf) {
+		keys = append(keys, sub.known()...)
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+func (s *Schema) matchPatterns(key string) []*Schema {
+	var matched []*Schema
+	for pattern, re := range s.patterns {
+		if re.MatchString(key) {
+			matched = append(matched, s.PatternProperties[pattern])
+		}
+	}
+	return matched
+}
+
+// combine returns a schema that requires all (or any) of the given schemas to
+// match. Nil schemas allow anything, so they are left out when all schemas
+// must match and make the result nil when any of them may match.
+func combine(schemas []*Schema, all bool) *Schema {
+	var result []*Schema
+	for _, s := range schemas {
+		if s == nil {
+			if !all {
+				return nil
+			}
+			continue
+		}
+		result = append(result, s)
+	}
+	switch {
+	case len(result) == 0:
+		return nil
+	case len(result) == 1:
+		return result[0]
+	case all:
+		return &Schema{AllOf: result}
+	default:
+		return &Schema{AnyOf: result}
+	}
+}
+
+// DidYouMean returns the known key that is closest to the unknown key, if any
+// of them is close enough.
+func (k *UnknownKey) DidYouMean() string {
+	model := fuzzy.NewModel()
+	model.SetThreshold(1)
+	model.Train(k.Known)
+	return model.SpellCheck(k.Node.Value)
+}
+
+// UnknownKeys walks the given YAML document and returns every key that is not
+// allowed by the schema, in the order they appear in the document.
+func (s *Schema) UnknownKeys(node *yaml.Node) []*UnknownKey {
+	var unknown []*UnknownKey
+	var walk func(node *yaml.Node, s *Schema)
+	walk = func(node *yaml.Node, s *Schema) {
+		if s == nil || node == nil {
+			return
+		}
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				walk(child, s)
+			}
+		case yaml.AliasNode:
+			// Anchors are checked where they are defined
+		case yaml.SequenceNode:
+			items := s.items()
+			for _, child := range node.Content {
+				walk(child, items)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], node.Content[i+1]
+				// Merge keys insert the keys of another mapping, which are
+				// checked where that mapping is defined
+				if key.Tag == "!!merge" {
+					continue
+				}
+				child, ok := s.property(key.Value)
+				if !ok {
+					unknown = append(unknown, &UnknownKey{Node: key, Known: s.known()})
+					continue
+				}
+				walk(value, child)
+			}
+		}
+	}
+	walk(node, s)
+	return unknown
+}
@@ -0,0 +1,95 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/internal/schema"
+)
+
+const testSchema = `{
+  "definitions": {
+    "task": {
+      "type": "object",
+      "properties": {
+        "cmds": { "type": "array", "items": { "$ref": "#/definitions/cmd" } },
+        "vars": { "type": "object", "patternProperties": { "^.*$": {} } }
+      },
+      "additionalProperties": false
+    },
+    "cmd": {
+      "anyOf": [
+        { "type": "string" },
+        { "type": "object", "properties": { "cmd": {} }, "additionalProperties": false },
+        { "type": "object", "properties": { "task": {} }, "additionalProperties": false }
+      ]
+    }
+  },
+  "allOf": [
+    {
+      "type": "object",
+      "properties": {
+        "version": {},
+        "tasks": {
+          "type": "object",
+          "patternProperties": {
+            "^.*$": { "anyOf": [{ "type": "string" }, { "$ref": "#/definitions/task" }] }
+          }
+        }
+      },
+      "additionalProperties": false
+    },
+    { "anyOf": [{ "required": ["tasks"] }] }
+  ]
+}`
+
+func TestUnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	s, err := schema.Parse([]byte(testSchema))
+	require.NoError(t, err)
+
+	var doc yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`
+version: '3'
+verison: '3'
+defaults: &defaults
+  cmds: [echo]
+tasks:
+  short: echo
+  build:
+    <<: *defaults
+    vars:
+      ANY_NAME: 1
+    cmds:
+      - echo
+      - cmd: echo
+      - task: short
+      - cmd: echo
+        task: short
+        silnet: true
+    sorces: []
+`), &doc))
+
+	var got []string
+	for _, key := range s.UnknownKeys(&doc) {
+		got = append(got, key.Node.Value)
+	}
+	assert.Equal(t, []string{"verison", "defaults", "silnet", "sorces"}, got)
+}
+
+func TestDidYouMean(t *testing.T) {
+	t.Parallel()
+
+	key := &schema.UnknownKey{
+		Node:  &yaml.Node{Value: "sorces"},
+		Known: []string{"cmds", "sources", "vars"},
+	}
+	assert.Equal(t, "sources", key.DidYouMean())
+
+	key.Node.Value = "dependencies"
+	assert.Equal(t, "", key.DidYouMean())
+}
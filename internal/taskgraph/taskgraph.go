@@ -0,0 +1,202 @@
+// Package taskgraph holds the graph of tasks and the tasks they depend on or
+// call, and renders it in formats that can be embedded in documentation.
+package taskgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Supported output formats for [Graph.Write].
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+	FormatJSON    = "json"
+)
+
+// Formats is the list of formats supported by [Graph.Write].
+var Formats = []string{FormatDOT, FormatMermaid, FormatJSON}
+
+// EdgeType describes how a task uses another task.
+type EdgeType string
+
+const (
+	// EdgeDep means that the task lists the other task in its deps.
+	EdgeDep EdgeType = "dep"
+	// EdgeCall means that the task calls the other task from its cmds.
+	EdgeCall EdgeType = "call"
+)
+
+type (
+	// A Graph is a directed graph of tasks. Nodes and edges are kept in the
+	// order they were added so that the output is stable.
+	Graph struct {
+		Nodes []*Node `json:"nodes"`
+		Edges []*Edge `json:"edges"`
+		index map[string]*Node
+	}
+	// A Node is a single task in the graph.
+	Node struct {
+		Name     string    `json:"name"`
+		Desc     string    `json:"desc,omitempty"`
+		Location *Location `json:"location,omitempty"`
+	}
+	// A Location is the position of a task's definition in a Taskfile.
+	Location struct {
+		Taskfile string `json:"taskfile"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+	}
+	// An Edge points from a task to a task that it depends on or calls.
+	Edge struct {
+		From string   `json:"from"`
+		To   string   `json:"to"`
+		Type EdgeType `json:"type"`
+	}
+)
+
+// New creates an empty [Graph].
+func New() *Graph {
+	return &Graph{
+		Nodes: []*Node{},
+		Edges: []*Edge{},
+		index: map[string]*Node{},
+	}
+}
+
+// AddNode adds the given node to the graph. It returns false if a node with
+// the same name already exists.
+func (g *Graph) AddNode(node *Node) bool {
+	if _, ok := g.index[node.Name]; ok {
+		return false
+	}
+	g.index[node.Name] = node
+	g.Nodes = append(g.Nodes, node)
+	return true
+}
+
+// Node returns the node with the given name, if any.
+func (g *Graph) Node(name string) (*Node, bool) {
+	node, ok := g.index[name]
+	return node, ok
+}
+
+// AddEdge adds an edge to the graph unless the same edge already exists.
+func (g *Graph) AddEdge(from, to string, typ EdgeType) {
+	edge := &Edge{From: from, To: to, Type: typ}
+	if slices.ContainsFunc(g.Edges, func(e *Edge) bool { return *e == *edge }) {
+		return
+	}
+	g.Edges = append(g.Edges, edge)
+}
+
+// Cycle returns the first cycle that can be reached from the given nodes, or
+// from any node if none are given. The first node of the cycle is repeated at
+// the end. It returns nil if there are no cycles.
+func (g *Graph) Cycle(from ...string) []string {
+	if len(from) == 0 {
+		for _, node := range g.Nodes {
+			from = append(from, node.Name)
+		}
+	}
+
+	adjacent := make(map[string][]string, len(g.Nodes))
+	for _, edge := range g.Edges {
+		adjacent[edge.From] = append(adjacent[edge.From], edge.To)
+	}
+
+	// Depth-first search keeping track of the current path. A node that is
+	// reached again while it is still on the path closes a cycle.
+	const (
+		_ = iota // not visited yet
+		onPath
+		done
+	)
+	state := make(map[string]int, len(g.Nodes))
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case onPath:
+			start := slices.Index(path, name)
+			return append(slices.Clone(path[start:]), name)
+		case done:
+			return nil
+		}
+		state[name] = onPath
+		path = append(path, name)
+		for _, next := range adjacent[name] {
+			if cycle := visit(next); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return nil
+	}
+
+	for _, name := range from {
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// Write renders the graph in the given format.
+func (g *Graph) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatDOT:
+		return g.writeDOT(w)
+	case FormatMermaid:
+		return g.writeMermaid(w)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(g)
+	default:
+		return fmt.Errorf("task: unknown graph format %q, must be one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+func (g *Graph) writeDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph tasks {\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "  %q;\n", node.Name)
+	}
+	for _, edge := range g.Edges {
+		if edge.Type == EdgeCall {
+			fmt.Fprintf(&b, "  %q -> %q [style=dashed];\n", edge.From, edge.To)
+		} else {
+			fmt.Fprintf(&b, "  %q -> %q;\n", edge.From, edge.To)
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (g *Graph) writeMermaid(w io.Writer) error {
+	// Task names can contain characters that Mermaid doesn't allow in IDs,
+	// so each node gets a generated ID and the name is used as its label.
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+	for i, node := range g.Nodes {
+		ids[node.Name] = fmt.Sprintf("t%d", i)
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[node.Name], strings.ReplaceAll(node.Name, `"`, "#quot;"))
+	}
+	for _, edge := range g.Edges {
+		if edge.Type == EdgeCall {
+			fmt.Fprintf(&b, "  %s -.-> %s\n", ids[edge.From], ids[edge.To])
+		} else {
+			fmt.Fprintf(&b, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,71 @@
+package taskgraph_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/internal/taskgraph"
+)
+
+func newGraph() *taskgraph.Graph {
+	g := taskgraph.New()
+	g.AddNode(&taskgraph.Node{Name: "build"})
+	g.AddNode(&taskgraph.Node{Name: "gen:proto"})
+	g.AddNode(&taskgraph.Node{Name: "build"})
+	g.AddEdge("build", "gen:proto", taskgraph.EdgeDep)
+	g.AddEdge("build", "gen:proto", taskgraph.EdgeDep)
+	return g
+}
+
+func TestWriteMermaid(t *testing.T) {
+	t.Parallel()
+
+	var buff bytes.Buffer
+	require.NoError(t, newGraph().Write(&buff, taskgraph.FormatMermaid))
+	assert.Equal(t, `graph TD
+  t0["build"]
+  t1["gen:proto"]
+  t0 --> t1
+`, buff.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	var buff bytes.Buffer
+	require.NoError(t, newGraph().Write(&buff, taskgraph.FormatJSON))
+
+	var g taskgraph.Graph
+	require.NoError(t, json.Unmarshal(buff.Bytes(), &g))
+	assert.Len(t, g.Nodes, 2)
+	assert.Equal(t, []*taskgraph.Edge{{From: "build", To: "gen:proto", Type: taskgraph.EdgeDep}}, g.Edges)
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	assert.Error(t, newGraph().Write(&bytes.Buffer{}, "svg"))
+}
+
+func TestCycle(t *testing.T) {
+	t.Parallel()
+
+	g := taskgraph.New()
+	for _, name := range []string{"a", "b", "c", "d"} {
+		g.AddNode(&taskgraph.Node{Name: name})
+	}
+	g.AddEdge("a", "b", taskgraph.EdgeDep)
+	g.AddEdge("b", "c", taskgraph.EdgeCall)
+	g.AddEdge("d", "d", taskgraph.EdgeDep)
+
+	assert.Nil(t, g.Cycle("a"))
+	assert.Equal(t, []string{"d", "d"}, g.Cycle())
+
+	g.AddEdge("c", "a", taskgraph.EdgeDep)
+	assert.Equal(t, []string{"a", "b", "c", "a"}, g.Cycle("a"))
+	assert.Equal(t, []string{"b", "c", "a", "b"}, g.Cycle("b"))
+}
@@ -0,0 +1,92 @@
+package taskrc
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+var defaultConfigFilenames = []string{
+	".taskrc.yml",
+	".taskrc.yaml",
+}
+
+type (
+	// Config is the structure of a .taskrc.yml file.
+	Config struct {
+		Version     *semver.Version
+		Experiments map[string]int `yaml:"experiments"`
+		RemoteCache *RemoteCache   `yaml:"remote_cache"`
+	}
+	// RemoteCache configures a remote cache that task outputs can be
+	// downloaded from and uploaded to.
+	RemoteCache struct {
+		URL      string            `yaml:"url"`
+		ReadOnly bool              `yaml:"read_only"`
+		Headers  map[string]string `yaml:"headers"`
+		Timeout  time.Duration     `yaml:"timeout"`
+	}
+)
+
+var (
+	config     Config
+	configOnce sync.Once
+)
+
+// Get returns the contents of the .taskrc.yml file for the current run. The
+// file is only read once. If no file is found or it cannot be parsed, an empty
+// config is returned.
+func Get() *Config {
+	configOnce.Do(func() {
+		config = read()
+	})
+	return &config
+}
+
+// FilePath returns the path of the given filename in the directory of the
+// Taskfile being run.
+func FilePath(filename string) string {
+	// Parse the CLI flags again to get the directory/taskfile being run
+	// We use a flagset here so that we can parse a subset of flags without exiting on error.
+	var dir, taskfile string
+	fs := pflag.NewFlagSet("taskrc", pflag.ContinueOnError)
+	fs.StringVarP(&dir, "dir", "d", "", "Sets directory of execution.")
+	fs.StringVarP(&taskfile, "taskfile", "t", "", `Choose which Taskfile to run. Defaults to "Taskfile.yml".`)
+	fs.Usage = func() {}
+	_ = fs.Parse(os.Args[1:])
+	// If the directory is set, find the file in that directory.
+	if dir != "" {
+		return filepath.Join(dir, filename)
+	}
+	// If the taskfile is set, find the file in the directory containing the Taskfile.
+	if taskfile != "" {
+		return filepath.Join(filepath.Dir(taskfile), filename)
+	}
+	// Otherwise just use the current working directory.
+	return filename
+}
+
+func read() Config {
+	var cfg Config
+	var content []byte
+	var err error
+	for _, filename := range defaultConfigFilenames {
+		path := FilePath(filename)
+		content, err = os.ReadFile(path)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return Config{}
+	}
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return Config{}
+	}
+	return cfg
+}
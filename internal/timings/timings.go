@@ -0,0 +1,218 @@
+// Package timings measures where the time of a run is spent so that it can be
+// summarized once the run has finished.
+package timings
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ladicle/tabwriter"
+)
+
+type (
+	// A Recorder collects the timings of every task executed during a run. It
+	// is safe for concurrent use. All methods are no-ops on a nil Recorder, so
+	// callers don't need to check whether timings are enabled.
+	Recorder struct {
+		mutex sync.Mutex
+		tasks []*Task
+	}
+	// A Task holds the timings of a single task execution. All methods are
+	// no-ops on a nil Task.
+	Task struct {
+		Name  string
+		Start time.Time
+		End   time.Time
+		// Wait is the time spent waiting for a slot of the concurrency limit.
+		Wait time.Duration
+		// Deps is the time spent running the task's dependencies.
+		Deps time.Duration
+
+		recorder *Recorder
+		parent   *Task
+		deps     []*Task
+	}
+)
+
+type (
+	taskKey struct{}
+	depsKey struct{}
+)
+
+// New creates a new [Recorder].
+func New() *Recorder {
+	return &Recorder{}
+}
+
+// Start records the start of a task. The given wait is the time the task has
+// already spent waiting for the concurrency limit and counts towards its wall
+// time. If ctx was returned by [WithDeps], the task is recorded as a
+// dependency of the task that is running its deps. The returned context should
+// be passed to any work done by the task.
+func (r *Recorder) Start(ctx context.Context, name string, wait time.Duration) (context.Context, *Task) {
+	if r == nil {
+		return ctx, nil
+	}
+	t := &Task{
+		Name:     name,
+		Start:    time.Now().Add(-wait),
+		Wait:     wait,
+		recorder: r,
+	}
+	t.parent, _ = ctx.Value(taskKey{}).(*Task)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.tasks = append(r.tasks, t)
+	if deps, _ := ctx.Value(depsKey{}).(*Task); deps != nil && deps == t.parent {
+		t.parent.deps = append(t.parent.deps, t)
+	}
+
+	return context.WithValue(ctx, taskKey{}, t), t
+}
+
+// FromContext returns the task that is running in ctx, if any.
+func FromContext(ctx context.Context) *Task {
+	t, _ := ctx.Value(taskKey{}).(*Task)
+	return t
+}
+
+// WithDeps returns a context for running the dependencies of the task that is
+// running in ctx.
+func WithDeps(ctx context.Context) context.Context {
+	t := FromContext(ctx)
+	if t == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, depsKey{}, t)
+}
+
+// Finish records the end of the task.
+func (t *Task) Finish() {
+	if t == nil {
+		return
+	}
+	t.recorder.mutex.Lock()
+	defer t.recorder.mutex.Unlock()
+	t.End = time.Now()
+}
+
+// AddWait adds d to the time the task spent waiting for the concurrency limit.
+func (t *Task) AddWait(d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.recorder.mutex.Lock()
+	defer t.recorder.mutex.Unlock()
+	t.Wait += d
+}
+
+// WaitFor calls f, which is expected to block until a slot of the concurrency
+// limit is available, and adds the time it took to the task's wait time.
+func (t *Task) WaitFor(f func()) {
+	start := time.Now()
+	f()
+	t.AddWait(time.Since(start))
+}
+
+// AddDeps adds d to the time the task spent running its dependencies.
+func (t *Task) AddDeps(d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.recorder.mutex.Lock()
+	defer t.recorder.mutex.Unlock()
+	t.Deps += d
+}
+
+// Wall returns the total time the task took, including its dependencies.
+func (t *Task) Wall() time.Duration {
+	if t.End.IsZero() {
+		return 0
+	}
+	return t.End.Sub(t.Start)
+}
+
+// CriticalPath returns the chain of tasks that determined the duration of the
+// run. It starts at the slowest task that was called directly and follows the
+// dependency that finished last for each task, since a task can't start its
+// own commands before all of its dependencies have finished.
+func (r *Recorder) CriticalPath() []*Task {
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var roots []*Task
+	for _, t := range r.tasks {
+		if t.parent == nil {
+			roots = append(roots, t)
+		}
+	}
+
+	var path []*Task
+	for next := slowest(roots); next != nil; next = last(next.deps) {
+		path = append(path, next)
+	}
+	return path
+}
+
+func slowest(tasks []*Task) *Task {
+	var result *Task
+	for _, t := range tasks {
+		if result == nil || t.Wall() > result.Wall() {
+			result = t
+		}
+	}
+	return result
+}
+
+func last(tasks []*Task) *Task {
+	var result *Task
+	for _, t := range tasks {
+		if result == nil || t.End.After(result.End) {
+			result = t
+		}
+	}
+	return result
+}
+
+// Write writes a table of the timings of every task followed by the critical
+// path to w.
+func (r *Recorder) Write(w io.Writer) error {
+	if r == nil {
+		return nil
+	}
+	path := r.CriticalPath()
+
+	r.mutex.Lock()
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "TASK\tWALL\tWAIT\tDEPS\tSELF")
+	for _, t := range r.tasks {
+		self := t.Wall() - t.Wait - t.Deps
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.Name, round(t.Wall()), round(t.Wait), round(t.Deps), round(self))
+	}
+	r.mutex.Unlock()
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(path) == 0 {
+		return nil
+	}
+	names := make([]string, len(path))
+	for i, t := range path {
+		names[i] = fmt.Sprintf("%s (%s)", t.Name, round(t.Wall()))
+	}
+	_, err := fmt.Fprintf(w, "\nCritical path: %s\n", strings.Join(names, " -> "))
+	return err
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Millisecond)
+}
@@ -0,0 +1,54 @@
+package timings_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/internal/timings"
+)
+
+func TestCriticalPath(t *testing.T) {
+	t.Parallel()
+
+	r := timings.New()
+	ctx, root := r.Start(context.Background(), "root", 0)
+	depsCtx := timings.WithDeps(ctx)
+
+	_, fast := r.Start(depsCtx, "fast", 0)
+	slowCtx, slow := r.Start(depsCtx, "slow", 0)
+	fast.Finish()
+
+	// Tasks called from commands are not dependencies
+	_, call := r.Start(slowCtx, "call", 0)
+	time.Sleep(time.Millisecond)
+	call.Finish()
+	slow.Finish()
+	root.Finish()
+
+	path := r.CriticalPath()
+	require.Len(t, path, 2)
+	assert.Equal(t, "root", path[0].Name)
+	assert.Equal(t, "slow", path[1].Name)
+
+	var buff bytes.Buffer
+	require.NoError(t, r.Write(&buff))
+	assert.Contains(t, buff.String(), "TASK")
+	assert.Contains(t, buff.String(), "Critical path: root")
+}
+
+func TestWait(t *testing.T) {
+	t.Parallel()
+
+	r := timings.New()
+	ctx, task := r.Start(context.Background(), "task", time.Second)
+	timings.FromContext(ctx).WaitFor(func() {})
+	task.Finish()
+
+	assert.GreaterOrEqual(t, task.Wait, time.Second)
+	assert.GreaterOrEqual(t, task.Wall(), time.Second)
+}
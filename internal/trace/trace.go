@@ -0,0 +1,156 @@
+// Package trace records spans of work done by the executor and writes them in
+// the Chrome Trace Event format, which can be opened in Perfetto or
+// chrome://tracing.
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+type (
+	// A Tracer records spans. It is safe for concurrent use. All methods are
+	// no-ops on a nil Tracer, so callers don't need to check whether tracing
+	// is enabled.
+	Tracer struct {
+		mutex  sync.Mutex
+		start  time.Time
+		events []event
+		// lanes holds the stack of open spans for each thread ID. Spans on the
+		// same lane must be strictly nested for trace viewers to display them
+		// correctly, so concurrent spans are put on separate lanes.
+		lanes [][]*Span
+	}
+	// A Span is a single unit of work in a trace.
+	Span struct {
+		tracer *Tracer
+		name   string
+		cat    string
+		args   map[string]any
+		start  time.Time
+		lane   int
+	}
+	// event is a complete event ("ph": "X") in the Chrome Trace Event format.
+	event struct {
+		Name  string         `json:"name"`
+		Cat   string         `json:"cat,omitempty"`
+		Phase string         `json:"ph"`
+		TS    float64        `json:"ts"`
+		Dur   float64        `json:"dur"`
+		PID   int            `json:"pid"`
+		TID   int            `json:"tid"`
+		Args  map[string]any `json:"args,omitempty"`
+	}
+)
+
+type spanKey struct{}
+
+// New creates a new [Tracer]. All timestamps are relative to the time it was
+// created.
+func New() *Tracer {
+	return &Tracer{start: time.Now()}
+}
+
+// Start starts a new span with the given name and category. If ctx contains a
+// span, the new span is nested under it when possible. The returned context
+// contains the new span and should be passed to any work done inside of it.
+func (t *Tracer) Start(ctx context.Context, name, cat string, args map[string]any) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	parent, _ := ctx.Value(spanKey{}).(*Span)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	lane := -1
+	// Share the lane of the parent if nothing else is running on it yet
+	if parent != nil {
+		stack := t.lanes[parent.lane]
+		if len(stack) > 0 && stack[len(stack)-1] == parent {
+			lane = parent.lane
+		}
+	}
+	// Otherwise, pick the first lane that is free
+	if lane == -1 {
+		for i, stack := range t.lanes {
+			if len(stack) == 0 {
+				lane = i
+				break
+			}
+		}
+	}
+	if lane == -1 {
+		lane = len(t.lanes)
+		t.lanes = append(t.lanes, nil)
+	}
+
+	span := &Span{
+		tracer: t,
+		name:   name,
+		cat:    cat,
+		args:   args,
+		start:  time.Now(),
+		lane:   lane,
+	}
+	t.lanes[lane] = append(t.lanes[lane], span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// End ends the span and records it in the trace.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	end := time.Now()
+	t := s.tracer
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stack := t.lanes[s.lane]
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i] == s {
+			t.lanes[s.lane] = append(stack[:i], stack[i+1:]...)
+			break
+		}
+	}
+	t.events = append(t.events, event{
+		Name:  s.name,
+		Cat:   s.cat,
+		Phase: "X",
+		TS:    micros(s.start.Sub(t.start)),
+		Dur:   micros(end.Sub(s.start)),
+		PID:   1,
+		TID:   s.lane + 1,
+		Args:  s.args,
+	})
+}
+
+// Write writes all recorded spans to w as a JSON trace.
+func (t *Tracer) Write(w io.Writer) error {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	events := t.events
+	if events == nil {
+		events = []event{}
+	}
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []event `json:"traceEvents"`
+		DisplayTimeUnit string  `json:"displayTimeUnit"`
+	}{
+		TraceEvents:     events,
+		DisplayTimeUnit: "ms",
+	})
+}
+
+func micros(d time.Duration) float64 {
+	return float64(d.Nanoseconds()) / 1000
+}
@@ -0,0 +1,62 @@
+package trace_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/internal/trace"
+)
+
+type traceFile struct {
+	TraceEvents []struct {
+		Name  string  `json:"name"`
+		Phase string  `json:"ph"`
+		TS    float64 `json:"ts"`
+		Dur   float64 `json:"dur"`
+		TID   int     `json:"tid"`
+	} `json:"traceEvents"`
+}
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+
+	tracer := trace.New()
+	ctx, root := tracer.Start(context.Background(), "root", "task", nil)
+	// The first child can share the lane of its parent...
+	_, a := tracer.Start(ctx, "a", "task", nil)
+	// ...but a concurrent sibling can't
+	_, b := tracer.Start(ctx, "b", "task", nil)
+	b.End()
+	a.End()
+	root.End()
+
+	var buff bytes.Buffer
+	require.NoError(t, tracer.Write(&buff))
+
+	var f traceFile
+	require.NoError(t, json.Unmarshal(buff.Bytes(), &f))
+	require.Len(t, f.TraceEvents, 3)
+
+	tids := map[string]int{}
+	for _, ev := range f.TraceEvents {
+		assert.Equal(t, "X", ev.Phase)
+		tids[ev.Name] = ev.TID
+	}
+	assert.Equal(t, tids["root"], tids["a"])
+	assert.NotEqual(t, tids["a"], tids["b"])
+}
+
+func TestNilTracer(t *testing.T) {
+	t.Parallel()
+
+	var tracer *trace.Tracer
+	ctx, span := tracer.Start(context.Background(), "root", "task", nil)
+	assert.NotNil(t, ctx)
+	span.End()
+	require.NoError(t, tracer.Write(&bytes.Buffer{}))
+}
@@ -0,0 +1,64 @@
+package task
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-task/task/v3/errors"
+)
+
+// runKeepGoing runs the given calls without cancelling the others when one of
+// them fails, and returns the errors of every call that failed.
+func (e *Executor) runKeepGoing(ctx context.Context, calls []*Call) error {
+	errs := make([]error, len(calls))
+	names := make([]string, len(calls))
+	var wg sync.WaitGroup
+	for i, c := range calls {
+		names[i] = c.Task
+		if !e.Parallel {
+			errs[i] = e.RunTask(ctx, c)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = e.RunTask(ctx, c)
+		}()
+	}
+	wg.Wait()
+	return joinTaskErrors(names, errs)
+}
+
+// joinTaskErrors combines the errors of the tasks with the given names into a
+// single error. A single error is returned as it is, while more errors are
+// combined into a [errors.TasksFailedError] that lists each of them, including
+// the errors of tasks that kept going further down.
+func joinTaskErrors(names []string, errs []error) error {
+	var all []error
+	var last error
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		last = err
+		var failedErr *errors.TasksFailedError
+		if errors.As(err, &failedErr) {
+			all = append(all, failedErr.Errors...)
+			continue
+		}
+		// Errors of tasks that were called by other tasks, like exit codes,
+		// don't say which task failed
+		if _, ok := err.(errors.TaskError); !ok {
+			err = &errors.TaskRunError{TaskName: names[i], Err: err}
+		}
+		all = append(all, err)
+	}
+	switch len(all) {
+	case 0:
+		return nil
+	case 1:
+		return last
+	default:
+		return &errors.TasksFailedError{Errors: all}
+	}
+}
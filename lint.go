@@ -0,0 +1,324 @@
+package task
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/fingerprint"
+	"github.com/go-task/task/v3/internal/lint"
+	"github.com/go-task/task/v3/taskfile"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+var templateRegex = regexp.MustCompile(`\{\{.*?\}\}`)
+
+// Lint checks the Taskfile for common mistakes and returns the issues found,
+// sorted by their location.
+// Unlike running a task, it doesn't compile any of the tasks, so references
+// to tasks and variables that contain templates are only checked as far as
+// possible without evaluating them.
+func (e *Executor) Lint() []*lint.Issue {
+	l := &linter{
+		e:     e,
+		files: map[string][]string{},
+	}
+	l.checkReferences()
+	l.checkTasks()
+	l.checkAliases()
+	slices.SortStableFunc(l.issues, func(a, b *lint.Issue) int {
+		return cmp.Or(
+			cmp.Compare(a.Taskfile, b.Taskfile),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Column, b.Column),
+		)
+	})
+	return l.issues
+}
+
+type linter struct {
+	e      *Executor
+	issues []*lint.Issue
+	files  map[string][]string
+}
+
+// reference is a call to a task from the deps or cmds of another task.
+type reference struct {
+	from *ast.Task
+	name string
+	vars *ast.Vars
+}
+
+func (l *linter) references() []reference {
+	var refs []reference
+	for t := range l.e.Taskfile.Tasks.Values(nil) {
+		for _, d := range t.Deps {
+			if d != nil && d.Task != "" {
+				refs = append(refs, reference{from: t, name: d.Task, vars: d.Vars})
+			}
+		}
+		for _, c := range t.Cmds {
+			if c != nil && c.Task != "" {
+				refs = append(refs, reference{from: t, name: c.Task, vars: c.Vars})
+			}
+		}
+	}
+	return refs
+}
+
+// checkReferences reports calls to tasks that don't exist, calls that don't
+// provide the variables required by the called task and internal tasks that
+// are never called.
+func (l *linter) checkReferences() {
+	used := map[string]bool{}
+	var patterns []*regexp.Regexp
+
+	for _, ref := range l.references() {
+		// Task names with templates can only be resolved by compiling the
+		// task, so we only use them to find out which tasks might be called.
+		if strings.Contains(ref.name, "{{") {
+			patterns = append(patterns, templatePattern(ref.name))
+			continue
+		}
+		t, err := l.e.GetTask(&Call{Task: ref.name})
+		if err != nil {
+			l.report(lint.SeverityError, lint.RuleMissingTask, ref.from, ref.name,
+				"task %q calls task %q which does not exist", ref.from.Task, ref.name)
+			continue
+		}
+		if t.Task != ref.from.Task {
+			used[t.Task] = true
+		}
+		if t.Requires == nil {
+			continue
+		}
+		for _, v := range t.Requires.Vars {
+			if !l.provides(ref, t, v.Name) {
+				l.report(lint.SeverityWarning, lint.RuleMissingRequiredVar, ref.from, ref.name,
+					"task %q calls task %q without the required variable %q", ref.from.Task, t.Task, v.Name)
+			}
+		}
+	}
+
+	for t := range l.e.Taskfile.Tasks.Values(nil) {
+		if !t.Internal || used[t.Task] {
+			continue
+		}
+		names := append([]string{t.Task}, t.Aliases...)
+		if slices.ContainsFunc(patterns, func(p *regexp.Regexp) bool {
+			return slices.ContainsFunc(names, p.MatchString)
+		}) {
+			continue
+		}
+		l.report(lint.SeverityWarning, lint.RuleUnusedInternal, t, "",
+			"internal task %q is never called by another task", t.Task)
+	}
+}
+
+// provides returns whether the given variable is set when the task is called
+// from the given reference. Variables can also be given on the command line or
+// through the environment, so a variable that is never provided only results
+// in a warning.
+func (l *linter) provides(ref reference, t *ast.Task, name string) bool {
+	for _, vars := range []*ast.Vars{
+		ref.vars,
+		t.Vars,
+		t.IncludeVars,
+		t.IncludedTaskfileVars,
+		l.e.Taskfile.Vars,
+		l.e.Taskfile.Env,
+	} {
+		if _, ok := vars.Get(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTasks reports problems with the settings of each task.
+func (l *linter) checkTasks() {
+	if l.e.Taskfile.Method != "" {
+		if _, err := fingerprint.NewSourcesChecker(l.e.Taskfile.Method, "", false); err != nil {
+			l.reportTaskfile(lint.RuleInvalidMethod, "method", "invalid method %q", l.e.Taskfile.Method)
+		}
+	}
+	if _, err := l.e.GetHash(&ast.Task{}); err != nil {
+		l.reportTaskfile(lint.RuleInvalidRun, "run", "invalid run %q", l.e.Taskfile.Run)
+	}
+
+	for t := range l.e.Taskfile.Tasks.Values(nil) {
+		if len(t.Generates) > 0 && len(t.Sources) == 0 {
+			l.report(lint.SeverityWarning, lint.RuleGeneratesWithoutSources, t, "",
+				"task %q has generates but no sources, so it will never be up-to-date", t.Task)
+		}
+		if t.Method != "" {
+			if _, err := fingerprint.NewSourcesChecker(t.Method, "", false); err != nil {
+				l.report(lint.SeverityError, lint.RuleInvalidMethod, t, t.Method,
+					"task %q has an invalid method %q", t.Task, t.Method)
+			}
+		}
+		if t.Run != "" {
+			if _, err := l.e.GetHash(t); err != nil {
+				l.report(lint.SeverityError, lint.RuleInvalidRun, t, t.Run,
+					"task %q has an invalid run %q", t.Task, t.Run)
+			}
+		}
+	}
+}
+
+// checkAliases reports aliases that are used by more than one task or that
+// are the name of another task.
+func (l *linter) checkAliases() {
+	owners := map[string]string{}
+	for t := range l.e.Taskfile.Tasks.Values(nil) {
+		for _, alias := range t.Aliases {
+			if other, ok := l.e.Taskfile.Tasks.Get(alias); ok && other.Task != t.Task {
+				l.report(lint.SeverityError, lint.RuleDuplicateAlias, t, alias,
+					"alias %q of task %q is also the name of a task", alias, t.Task)
+				continue
+			}
+			if owner, ok := owners[alias]; ok && owner != t.Task {
+				l.report(lint.SeverityError, lint.RuleDuplicateAlias, t, alias,
+					"alias %q of task %q is also an alias of task %q", alias, t.Task, owner)
+				continue
+			}
+			owners[alias] = t.Task
+		}
+	}
+}
+
+func (l *linter) report(severity lint.Severity, rule string, t *ast.Task, token string, format string, a ...any) {
+	issue := &lint.Issue{
+		Severity: severity,
+		Rule:     rule,
+		Message:  fmt.Sprintf(format, a...),
+		Task:     t.Task,
+	}
+	if t.Location != nil {
+		issue.Taskfile = t.Location.Taskfile
+		issue.Line = t.Location.Line
+		issue.Column = t.Location.Column
+		if token != "" {
+			if line, column, ok := l.find(t.Location.Taskfile, t.Location.Line, t.Location.Column, token); ok {
+				issue.Line, issue.Column = line, column
+			}
+		}
+	}
+	l.add(issue)
+}
+
+// reportTaskfile reports an error in a top-level key of the root Taskfile.
+func (l *linter) reportTaskfile(rule string, key string, format string, a ...any) {
+	issue := &lint.Issue{
+		Severity: lint.SeverityError,
+		Rule:     rule,
+		Message:  fmt.Sprintf(format, a...),
+		Taskfile: l.e.Taskfile.Location,
+	}
+	for i, text := range l.lines(issue.Taskfile) {
+		if strings.HasPrefix(text, key+":") {
+			issue.Line, issue.Column = i+1, 1
+			break
+		}
+	}
+	l.add(issue)
+}
+
+func (l *linter) add(issue *lint.Issue) {
+	if lines := l.lines(issue.Taskfile); lines != nil && issue.Line > 0 {
+		issue.Snippet = taskfile.NewSnippet([]byte(strings.Join(lines, "\n")),
+			taskfile.SnippetWithLine(issue.Line),
+			taskfile.SnippetWithColumn(issue.Column),
+			taskfile.SnippetWithPadding(2),
+		).String()
+	}
+	l.issues = append(l.issues, issue)
+}
+
+// find returns the position of the first occurrence of the given token in the
+// definition of the task at the given line and column, which ends at the first
+// line that isn't indented further than the name of the task. Tasks from
+// included Taskfiles reference other tasks by their name without the
+// namespace, so if the token can't be found, it is searched for again with
+// each of its namespaces removed.
+func (l *linter) find(path string, line, column int, token string) (int, int, bool) {
+	lines := l.lines(path)
+	for {
+		for i := line; i < len(lines); i++ {
+			text := lines[i]
+			indent := len(text) - len(strings.TrimLeft(text, " "))
+			trimmed := strings.TrimSpace(text)
+			if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			if indent < column {
+				break
+			}
+			if index := indexToken(text, token); index >= 0 {
+				return i + 1, index + 1, true
+			}
+		}
+		_, rest, ok := strings.Cut(token, ast.NamespaceSeparator)
+		if !ok || rest == "" {
+			return 0, 0, false
+		}
+		token = rest
+	}
+}
+
+// lines returns the lines of the Taskfile at the given path. Taskfiles that
+// can't be read, like remote ones, have no lines.
+func (l *linter) lines(path string) []string {
+	if lines, ok := l.files[path]; ok {
+		return lines
+	}
+	var lines []string
+	if b, err := os.ReadFile(path); err == nil {
+		lines = strings.Split(string(b), "\n")
+	}
+	l.files[path] = lines
+	return lines
+}
+
+// indexToken returns the index of the first occurrence of token in s that is
+// not part of a longer name, or -1 if there is none.
+func indexToken(s, token string) int {
+	isNameChar := func(i int) bool {
+		if i < 0 || i >= len(s) {
+			return false
+		}
+		c := s[i]
+		return c == '_' || c == '-' || c == '.' || c == ':' || c == '*' ||
+			('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+	}
+	for offset := 0; ; {
+		index := strings.Index(s[offset:], token)
+		if index < 0 {
+			return -1
+		}
+		index += offset
+		if !isNameChar(index-1) && !isNameChar(index+len(token)) {
+			return index
+		}
+		offset = index + 1
+	}
+}
+
+// templatePattern converts a task name that contains templates into a regular
+// expression that matches any name that the templates could evaluate to.
+func templatePattern(name string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, match := range templateRegex.FindAllStringIndex(name, -1) {
+		b.WriteString(regexp.QuoteMeta(name[last:match[0]]))
+		b.WriteString(".*")
+		last = match[1]
+	}
+	b.WriteString(regexp.QuoteMeta(name[last:]))
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-task/task/v3/errors"
 	"github.com/go-task/task/v3/internal/env"
+	"github.com/go-task/task/v3/internal/events"
 	"github.com/go-task/task/v3/internal/execext"
 	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/taskfile/ast"
@@ -24,6 +25,7 @@ func (e *Executor) areTaskPreconditionsMet(ctx context.Context, t *ast.Task) (bo
 			if !errors.Is(err, context.Canceled) {
 				e.Logger.Errf(logger.Magenta, "task: %s\n", p.Msg)
 			}
+			e.events.Emit(events.Event{Type: events.PreconditionFailed, Task: t.Name(), Cmd: p.Sh, Message: p.Msg})
 			return false, ErrPreconditionFailed
 		}
 	}
@@ -0,0 +1,49 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile/ast"
+
+	"mvdan.cc/sh/v3/interp"
+)
+
+// retry calls run until it succeeds or the given retries are used up. run is
+// given the number of the current attempt, starting at zero. Only errors with
+// an exit status are retried, since anything else, like a missing task or a
+// cancelled context, would fail again in the same way.
+func (e *Executor) retry(ctx context.Context, retries *ast.Retries, name string, run func(attempt int) error) error {
+	for attempt := 0; ; attempt++ {
+		err := run(attempt)
+		if err == nil || retries == nil {
+			return err
+		}
+		exitCode, isExitError := interp.IsExitStatus(err)
+		if !isExitError || ctx.Err() != nil {
+			return err
+		}
+		if !retries.ShouldRetry(attempt, int(exitCode)) {
+			if attempt > 0 {
+				e.Logger.Errf(logger.Red, "task: [%s] attempt %d of %d failed with exit code %d\n", name, attempt+1, retries.Count+1, exitCode)
+			}
+			return err
+		}
+
+		delay := retries.DelayBefore(attempt + 1)
+		e.Logger.Errf(logger.Yellow, "task: [%s] attempt %d of %d failed with exit code %d, retrying in %s\n", name, attempt+1, retries.Count+1, exitCode, delay)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// retryCall returns a copy of the given call for the given retry attempt.
+func retryCall(call *Call, attempt int) *Call {
+	c := *call
+	c.RetryAttempt = attempt
+	return &c
+}
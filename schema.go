@@ -0,0 +1,17 @@
+package task
+
+import (
+	_ "embed"
+	"sync"
+
+	"github.com/go-task/task/v3/internal/schema"
+)
+
+//go:embed website/static/schema.json
+var schemaJSON []byte
+
+// taskfileSchema returns the JSON Schema of a Taskfile. It is used to find keys
+// in a Taskfile that Task doesn't know about.
+var taskfileSchema = sync.OnceValues(func() (*schema.Schema, error) {
+	return schema.Parse(schemaJSON)
+})
@@ -1,6 +1,7 @@
 package task
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
@@ -8,16 +9,22 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/sajari/fuzzy"
 
 	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/artifact"
 	"github.com/go-task/task/v3/internal/env"
+	"github.com/go-task/task/v3/internal/events"
 	"github.com/go-task/task/v3/internal/execext"
 	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/junit"
 	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/internal/output"
+	"github.com/go-task/task/v3/internal/report"
+	"github.com/go-task/task/v3/internal/timings"
 	"github.com/go-task/task/v3/internal/version"
 	"github.com/go-task/task/v3/taskfile"
 	"github.com/go-task/task/v3/taskfile/ast"
@@ -32,11 +39,16 @@ func (e *Executor) Setup() error {
 	if err := e.setupTempDir(); err != nil {
 		return err
 	}
+	e.setupArtifactCache()
 	if err := e.readTaskfile(node); err != nil {
 		return err
 	}
 	e.setupFuzzyModel()
 	e.setupStdFiles()
+	e.setupReport()
+	e.setupEvents()
+	e.setupTimings()
+	e.setupJUnit()
 	if err := e.setupOutput(); err != nil {
 		return err
 	}
@@ -67,9 +79,20 @@ func (e *Executor) readTaskfile(node taskfile.Node) error {
 	debugFunc := func(s string) {
 		e.Logger.VerboseOutf(logger.Magenta, s)
 	}
+	warnFunc := func(s string) {
+		e.Logger.Warnf("%s", s)
+	}
 	promptFunc := func(s string) error {
 		return e.Logger.Prompt(logger.Yellow, s, "n", "y", "yes")
 	}
+	traceFunc := func(location string) func() {
+		_, span := e.Tracer.Start(context.Background(), location, "taskfile", nil)
+		return span.End
+	}
+	schema, err := taskfileSchema()
+	if err != nil {
+		return err
+	}
 	reader := taskfile.NewReader(
 		node,
 		taskfile.ReaderWithInsecure(e.Insecure),
@@ -77,8 +100,12 @@ func (e *Executor) readTaskfile(node taskfile.Node) error {
 		taskfile.ReaderWithOffline(e.Offline),
 		taskfile.ReaderWithTimeout(e.Timeout),
 		taskfile.ReaderWithTempDir(e.TempDir.Remote),
+		taskfile.ReaderWithSchema(schema),
+		taskfile.ReaderWithStrict(e.Strict),
 		taskfile.ReaderWithDebugFunc(debugFunc),
+		taskfile.ReaderWithWarnFunc(warnFunc),
 		taskfile.ReaderWithPromptFunc(promptFunc),
+		taskfile.ReaderWithTraceFunc(traceFunc),
 	)
 	graph, err := reader.Read()
 	if err != nil {
@@ -154,6 +181,17 @@ func (e *Executor) setupTempDir() error {
 	return nil
 }
 
+func (e *Executor) setupArtifactCache() {
+	if !e.Cache && e.RemoteCache == nil {
+		return
+	}
+	e.artifactCache = artifact.NewCache(
+		filepathext.SmartJoin(e.TempDir.Fingerprint, "artifacts"),
+		artifact.CacheWithMaxSize(e.CacheMaxSize),
+		artifact.CacheWithRemote(e.RemoteCache),
+	)
+}
+
 func (e *Executor) setupStdFiles() {
 	if e.Stdin == nil {
 		e.Stdin = os.Stdin
@@ -166,6 +204,35 @@ func (e *Executor) setupStdFiles() {
 	}
 }
 
+func (e *Executor) setupEvents() {
+	if e.Events == nil && e.report == nil {
+		return
+	}
+	e.events = events.NewStream(e.Events)
+	e.events.Subscribe(e.report.Handle)
+}
+
+func (e *Executor) setupReport() {
+	if e.Report == "" {
+		return
+	}
+	e.report = report.New()
+}
+
+func (e *Executor) setupTimings() {
+	if !e.Timings {
+		return
+	}
+	e.timings = timings.New()
+}
+
+func (e *Executor) setupJUnit() {
+	if e.JUnit == nil {
+		return
+	}
+	e.junit = junit.New()
+}
+
 func (e *Executor) setupLogger() {
 	e.Logger = &logger.Logger{
 		Stdin:      e.Stdin,
@@ -182,10 +249,27 @@ func (e *Executor) setupOutput() error {
 	if !e.OutputStyle.IsSet() {
 		e.OutputStyle = e.Taskfile.Output
 	}
+	if e.OutputStyle.Name == "files" {
+		e.OutputStyle.Files.Dir = filepathext.SmartJoin(e.Dir, cmp.Or(e.OutputStyle.Files.Dir, output.DefaultFilesDir))
+	}
 
 	var err error
 	e.Output, err = output.BuildFor(&e.OutputStyle, e.Logger)
-	return err
+	if err != nil {
+		return err
+	}
+	if e.Timestamps != "" {
+		if !slices.Contains(output.TimestampStyles, e.OutputStyle.Name) {
+			return fmt.Errorf("task: output style %q does not support timestamps", e.OutputStyle.Name)
+		}
+		e.Output = output.Timestamped{Output: e.Output, Format: e.Timestamps, Start: time.Now()}
+	}
+	// Lines printed by Task itself go above the live view of running tasks
+	if progress, ok := e.Output.(*output.Progress); ok {
+		e.Logger.Stdout = progress.Writer(e.Logger.Stdout)
+		e.Logger.Stderr = progress.Writer(e.Logger.Stderr)
+	}
+	return nil
 }
 
 func (e *Executor) setupCompiler() error {
@@ -204,6 +288,7 @@ func (e *Executor) setupCompiler() error {
 		TaskfileEnv:    e.Taskfile.Env,
 		TaskfileVars:   e.Taskfile.Vars,
 		Logger:         e.Logger,
+		Tracer:         e.Tracer,
 	}
 	return nil
 }
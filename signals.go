@@ -1,10 +1,12 @@
 package task
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/go-task/task/v3/errors"
 	"github.com/go-task/task/v3/internal/logger"
 )
 
@@ -13,10 +15,17 @@ const maxInterruptSignals = 3
 // NOTE(@andreynering): This function intercepts SIGINT and SIGTERM signals
 // so the Task process is not killed immediately and processes running have
 // time to do cleanup work.
+//
+// The first signal cancels the tasks that are running: the signal is forwarded
+// to the commands that are running, which are killed if they don't stop within
+// their grace period, and tasks that haven't started yet don't run. Deferred
+// commands still run.
 func (e *Executor) InterceptInterruptSignals() {
 	ch := make(chan os.Signal, maxInterruptSignals)
 	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
 
+	e.interrupted, e.interrupt = context.WithCancelCause(context.Background())
+
 	go func() {
 		for i := range maxInterruptSignals {
 			sig := <-ch
@@ -27,6 +36,23 @@ func (e *Executor) InterceptInterruptSignals() {
 			}
 
 			e.Logger.Outf(logger.Yellow, "task: Signal received: %q\n", sig)
+			e.interrupt(&errors.TaskCancelledBySignalError{Signal: sig})
 		}
 	}()
 }
+
+// withInterrupt returns a copy of the given context that is cancelled when
+// Task receives a signal, if signals are intercepted.
+func (e *Executor) withInterrupt(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	if e.interrupted == nil {
+		return ctx, func() { cancel(nil) }
+	}
+	stop := context.AfterFunc(e.interrupted, func() {
+		cancel(context.Cause(e.interrupted))
+	})
+	return ctx, func() {
+		stop()
+		cancel(nil)
+	}
+}
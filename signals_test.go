@@ -10,6 +10,7 @@ package task_test
 
 import (
 	"bytes"
+	"cmp"
 	"errors"
 	"os"
 	"os/exec"
@@ -30,6 +31,8 @@ func TestSignalSentToProcessGroup(t *testing.T) {
 
 	testCases := map[string]struct {
 		args     []string
+		dir      string
+		signal   syscall.Signal
 		sendSigs int
 		want     []string
 		notWant  []string
@@ -93,6 +96,36 @@ func TestSignalSentToProcessGroup(t *testing.T) {
 				"task: Failed to run task \"default\": exit status 4\n",
 			},
 		},
+		"child ignores sigint for too long: is killed after kill_timeout and deferred cmds run": {
+			args:     []string{task, "--", SLEEPIT, "handle", "-sleep=10s", "-cleanup=5s"},
+			dir:      "testdata/kill_timeout",
+			sendSigs: 1,
+			want: []string{
+				"sleepit: ready\n",
+				"sleepit: work started\n",
+				"task: Signal received: \"interrupt\"\n",
+				"sleepit: got signal=interrupt count=1\n",
+				"sleepit: cleanup started\n",
+				"deferred ran\n",
+				// 137 = 128 + SIGKILL
+				"task: Failed to run task \"default\": exit status 137\n",
+			},
+			notWant: []string{
+				"sleepit: cleanup done\n",
+			},
+		},
+		"child does not handle sigterm: receives forwarded sigterm and terminates": {
+			args:     []string{task, "--", SLEEPIT, "default", "-sleep=10s"},
+			signal:   syscall.SIGTERM,
+			sendSigs: 1,
+			want: []string{
+				"sleepit: ready\n",
+				"sleepit: work started\n",
+				"task: Signal received: \"terminated\"\n",
+				// 143 = 128 + SIGTERM
+				"task: Failed to run task \"default\": exit status 143\n",
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -101,7 +134,7 @@ func TestSignalSentToProcessGroup(t *testing.T) {
 			sut := exec.Command(tc.args[0], tc.args[1:]...)
 			sut.Stdout = &out
 			sut.Stderr = &out
-			sut.Dir = "testdata/ignore_signals"
+			sut.Dir = cmp.Or(tc.dir, "testdata/ignore_signals")
 			// Create a new process group by setting the process group ID of the child
 			// to the child PID.
 			// By default, the child would inherit the process group of the parent, but
@@ -145,9 +178,12 @@ func TestSignalSentToProcessGroup(t *testing.T) {
 			// where the negative PID means the corresponding process group. Note that
 			// this negative PID works only as long as the caller of the kill(2) system
 			// call has a different PID, which is the case for this test.
-			for range tc.sendSigs - 1 {
-				if err := syscall.Kill(-sut.Process.Pid, syscall.SIGINT); err != nil {
-					t.Fatalf("sending INT signal to the process group: %v", err)
+			//
+			// Every case sends exactly sendSigs signals, of the given kind or SIGINT.
+			// They all expect the child to get at least one.
+			for range tc.sendSigs {
+				if err := syscall.Kill(-sut.Process.Pid, cmp.Or(tc.signal, syscall.SIGINT)); err != nil {
+					t.Fatalf("sending signal to the process group: %v", err)
 				}
 				time.Sleep(1 * time.Millisecond)
 			}
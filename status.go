@@ -3,8 +3,15 @@ package task
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Ladicle/tabwriter"
 
 	"github.com/go-task/task/v3/internal/fingerprint"
+	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
 
@@ -41,6 +48,40 @@ func (e *Executor) Status(ctx context.Context, calls ...*Call) error {
 	return nil
 }
 
+// Explain prints why each of the given tasks is or isn't up to date, without
+// running them or updating their fingerprints.
+func (e *Executor) Explain(ctx context.Context, calls ...*Call) error {
+	for _, call := range calls {
+		t, err := e.CompiledTask(call)
+		if err != nil {
+			return err
+		}
+
+		method := e.Taskfile.Method
+		if t.Method != "" {
+			method = t.Method
+		}
+
+		explanation, err := fingerprint.ExplainTask(ctx, t,
+			fingerprint.WithMethod(method),
+			fingerprint.WithTempDir(e.TempDir.Fingerprint),
+			fingerprint.WithLogger(e.Logger),
+		)
+		if err != nil {
+			return err
+		}
+		if explanation.UpToDate {
+			e.Logger.Outf(logger.Green, "task: Task %q is up to date:\n", t.Name())
+		} else {
+			e.Logger.Outf(logger.Yellow, "task: Task %q is not up to date:\n", t.Name())
+		}
+		for _, reason := range explanation.Reasons {
+			e.Logger.Outf(logger.Default, "  - %s\n", reason)
+		}
+	}
+	return nil
+}
+
 func (e *Executor) statusOnError(t *ast.Task) error {
 	method := t.Method
 	if method == "" {
@@ -52,3 +93,67 @@ func (e *Executor) statusOnError(t *ast.Task) error {
 	}
 	return checker.OnError(t)
 }
+
+func (e *Executor) statusOnSuccess(t *ast.Task) error {
+	method := t.Method
+	if method == "" {
+		method = e.Taskfile.Method
+	}
+	checker, err := fingerprint.NewSourcesChecker(method, e.TempDir.Fingerprint, e.Dry)
+	if err != nil {
+		return err
+	}
+	if recorder, ok := checker.(fingerprint.SuccessRecorder); ok {
+		return recorder.OnSuccess(t)
+	}
+	return nil
+}
+
+// ListFingerprints prints the fingerprints stored for the tasks of the
+// Taskfile, one for each set of variables a task was called with.
+func (e *Executor) ListFingerprints() error {
+	entries, err := fingerprint.NewStore(e.TempDir.Fingerprint).List()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		e.Logger.Outf(logger.Yellow, "task: No fingerprints stored\n")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(e.Stdout, 0, 8, 3, ' ', 0)
+	for _, entry := range entries {
+		// Show the hash of each variable instead of the one of all of them
+		// that ends the key
+		name := entry.Key
+		if len(entry.Vars) > 0 {
+			name = name[:strings.LastIndexByte(name, ' ')]
+		}
+		vars := make([]string, 0, len(entry.Vars))
+		for _, v := range slices.Sorted(maps.Keys(entry.Vars)) {
+			vars = append(vars, v+"="+entry.Vars[v])
+		}
+		e.Logger.FOutf(w, logger.Green, "%s", name)
+		e.Logger.FOutf(w, logger.Default, "\t%s\t%s\t%s\n", strings.Join(vars, " "), entry.Method, entry.Updated.Local().Format(time.DateTime))
+	}
+	return w.Flush()
+}
+
+// ClearFingerprints removes the fingerprints stored for the given task,
+// whatever the variables it was called with, so it runs again.
+func (e *Executor) ClearFingerprints(name string) error {
+	// Tasks that are no longer in the Taskfile can still be cleared
+	t, getErr := e.GetTask(&Call{Task: name})
+	if getErr == nil {
+		name = t.Task
+	}
+	n, err := fingerprint.NewStore(e.TempDir.Fingerprint).Clear(name)
+	if err != nil {
+		return err
+	}
+	if n == 0 && getErr != nil {
+		return getErr
+	}
+	e.Logger.Outf(logger.Green, "task: Removed %d fingerprint(s) of task %q\n", n, name)
+	return nil
+}
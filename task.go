@@ -1,23 +1,31 @@
 package task
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"slices"
 	"sync/atomic"
+	"time"
 
 	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/artifact"
 	"github.com/go-task/task/v3/internal/env"
+	"github.com/go-task/task/v3/internal/events"
 	"github.com/go-task/task/v3/internal/execext"
 	"github.com/go-task/task/v3/internal/fingerprint"
+	"github.com/go-task/task/v3/internal/junit"
 	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/internal/output"
 	"github.com/go-task/task/v3/internal/slicesext"
 	"github.com/go-task/task/v3/internal/sort"
 	"github.com/go-task/task/v3/internal/summary"
 	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/internal/timings"
+	"github.com/go-task/task/v3/internal/trace"
 	"github.com/go-task/task/v3/taskfile/ast"
 
 	"golang.org/x/sync/errgroup"
@@ -73,24 +81,47 @@ func (e *Executor) Run(ctx context.Context, calls ...*Call) error {
 		return nil
 	}
 
+	if err := e.checkTaskCycles(calls...); err != nil {
+		return err
+	}
+
 	regularCalls, watchCalls, err := e.splitRegularAndWatchCalls(calls...)
 	if err != nil {
 		return err
 	}
 
-	g, ctx := errgroup.WithContext(ctx)
-	for _, c := range regularCalls {
-		c := c
-		if e.Parallel {
-			g.Go(func() error { return e.RunTask(ctx, c) })
-		} else {
-			if err := e.RunTask(ctx, c); err != nil {
-				return err
+	if e.timings != nil && len(watchCalls) == 0 {
+		defer e.printTimings()
+	}
+	if e.report != nil && len(watchCalls) == 0 {
+		defer e.printReport()
+	}
+	if e.junit != nil && len(watchCalls) == 0 {
+		defer e.writeJUnit()
+	}
+
+	ctx, cancel := e.withInterrupt(ctx)
+	defer cancel()
+
+	if e.KeepGoing {
+		if err := e.runKeepGoing(ctx, regularCalls); err != nil {
+			return err
+		}
+	} else {
+		g, ctx := errgroup.WithContext(ctx)
+		for _, c := range regularCalls {
+			c := c
+			if e.Parallel {
+				g.Go(func() error { return e.RunTask(ctx, c) })
+			} else {
+				if err := e.RunTask(ctx, c); err != nil {
+					return err
+				}
 			}
 		}
-	}
-	if err := g.Wait(); err != nil {
-		return err
+		if err := g.Wait(); err != nil {
+			return err
+		}
 	}
 
 	if len(watchCalls) > 0 {
@@ -100,6 +131,30 @@ func (e *Executor) Run(ctx context.Context, calls ...*Call) error {
 	return nil
 }
 
+func (e *Executor) printTimings() {
+	e.Logger.Errf(logger.Default, "\n")
+	if err := e.timings.Write(e.Logger.Stderr); err != nil {
+		e.Logger.VerboseErrf(logger.Yellow, "task: unable to print timings: %v\n", err)
+	}
+}
+
+func (e *Executor) printReport() {
+	w := e.ReportWriter
+	if w == nil {
+		w = e.Logger.Stderr
+		e.Logger.Errf(logger.Default, "\n")
+	}
+	if err := e.report.Write(w, e.Report); err != nil {
+		e.Logger.VerboseErrf(logger.Yellow, "task: unable to print report: %v\n", err)
+	}
+}
+
+func (e *Executor) writeJUnit() {
+	if err := e.junit.Write(e.JUnit); err != nil {
+		e.Logger.Errf(logger.Yellow, "task: unable to write JUnit report: %v\n", err)
+	}
+}
+
 func (e *Executor) splitRegularAndWatchCalls(calls ...*Call) (regularCalls []*Call, watchCalls []*Call, err error) {
 	for _, c := range calls {
 		t, err := e.GetTask(c)
@@ -124,6 +179,7 @@ func (e *Executor) RunTask(ctx context.Context, call *Call) error {
 	}
 	if !shouldRunOnCurrentPlatform(t.Platforms) {
 		e.Logger.VerboseOutf(logger.Yellow, `task: %q not for current platform - ignored\n`, call.Task)
+		e.events.Emit(events.Event{Type: events.TaskSkipped, Task: t.Name(), Message: "not for current platform"})
 		return nil
 	}
 
@@ -147,15 +203,43 @@ func (e *Executor) RunTask(ctx context.Context, call *Call) error {
 		}
 	}
 
-	release := e.acquireConcurrencyLimit()
+	waitStart := time.Now()
+	release := e.acquireConcurrencyLimit(t)
 	defer release()
+	wait := time.Since(waitStart)
+
+	// Tasks that were waiting to run when the run was cancelled, like the
+	// remaining deps of a task, don't run at all
+	if err := ctx.Err(); err != nil {
+		return contextError(ctx, err)
+	}
 
-	return e.startExecution(ctx, t, func(ctx context.Context) error {
+	return e.startExecution(ctx, t, func(ctx context.Context) (err error) {
 		e.Logger.VerboseErrf(logger.Magenta, "task: %q started\n", call.Task)
+		ctx, timing := e.timings.Start(ctx, t.Name(), wait)
+		defer timing.Finish()
+		ctx, span := e.Tracer.Start(ctx, t.Name(), "task", nil)
+		defer span.End()
+		ctx, testCase := e.junit.Start(ctx, t)
+		start := time.Now()
+		e.events.Emit(events.Event{Type: events.TaskStarted, Task: t.Name()})
+		skipped := false
+		defer func() {
+			testCase.Finish(err)
+			if !skipped {
+				e.events.EmitResult(events.Event{Type: events.TaskFinished, Task: t.Name()}, start, err, events.TaskFailed)
+			}
+		}()
+
 		if err := e.runDeps(ctx, t); err != nil {
 			return err
 		}
 
+		cacheKey, err := e.artifactKey(t)
+		if err != nil {
+			return err
+		}
+
 		skipFingerprinting := e.ForceAll || (!call.Indirect && e.Force)
 		if !skipFingerprinting {
 			if err := ctx.Err(); err != nil {
@@ -173,27 +257,54 @@ func (e *Executor) RunTask(ctx context.Context, call *Call) error {
 				method = t.Method
 			}
 
+			_, span := e.Tracer.Start(ctx, "fingerprint", "fingerprint", map[string]any{"method": method})
 			upToDate, err := fingerprint.IsTaskUpToDate(ctx, t,
 				fingerprint.WithMethod(method),
 				fingerprint.WithTempDir(e.TempDir.Fingerprint),
 				fingerprint.WithDry(e.Dry),
 				fingerprint.WithLogger(e.Logger),
 			)
+			span.End()
 			if err != nil {
 				return err
 			}
 
 			if upToDate && preCondMet {
+				skipped = true
+				testCase.Skip("up to date")
+				e.events.EmitResult(events.Event{Type: events.TaskUpToDate, Task: t.Name()}, start, nil, "")
 				if e.Verbose || (!call.Silent && !t.Silent && !e.Taskfile.Silent && !e.Silent) {
 					e.Logger.Errf(logger.Magenta, "task: Task %q is up to date\n", t.Name())
 				}
 				return nil
 			}
+
+			restored, err := e.restoreFromCache(ctx, t, cacheKey)
+			if artifact.IsTimeout(err) {
+				e.Logger.Errf(logger.Yellow, "task: Remote cache timed out, %q will run instead of being restored: %v\n", t.Name(), err)
+			} else if err != nil {
+				e.Logger.VerboseErrf(logger.Yellow, "task: unable to restore %q from cache: %v\n", t.Name(), err)
+			}
+			if restored {
+				if err := e.statusOnSuccess(t); err != nil {
+					e.Logger.VerboseErrf(logger.Yellow, "task: error recording status on success: %v\n", err)
+				}
+				skipped = true
+				testCase.Skip("restored from cache")
+				e.events.EmitResult(events.Event{Type: events.TaskRestored, Task: t.Name()}, start, nil, "")
+				if e.Verbose || (!call.Silent && !t.Silent && !e.Taskfile.Silent && !e.Silent) {
+					e.Logger.Errf(logger.Magenta, "task: Task %q restored from cache\n", t.Name())
+				}
+				return nil
+			}
 		}
 
 		for _, p := range t.Prompt {
 			if p != "" && !e.Dry {
-				if err := e.Logger.Prompt(logger.Yellow, p, "n", "y", "yes"); errors.Is(err, logger.ErrNoTerminal) {
+				resume := e.pauseOutput()
+				err := e.Logger.Prompt(logger.Yellow, p, "n", "y", "yes")
+				resume()
+				if errors.Is(err, logger.ErrNoTerminal) {
 					return &errors.TaskCancelledNoTerminalError{TaskName: call.Task}
 				} else if errors.Is(err, logger.ErrPromptCancelled) {
 					return &errors.TaskCancelledByUserError{TaskName: call.Task}
@@ -207,34 +318,92 @@ func (e *Executor) RunTask(ctx context.Context, call *Call) error {
 			e.Logger.Errf(logger.Red, "task: cannot make directory %q: %v\n", t.Dir, err)
 		}
 
-		var deferredExitCode uint8
+		ctx, closeOutput := e.wrapTaskOutput(ctx, t, call)
+		defer func() {
+			if closeErr := closeOutput(err); closeErr != nil {
+				e.Logger.Errf(logger.Red, "task: unable to close writer: %v\n", closeErr)
+			}
+		}()
 
-		for i := range t.Cmds {
-			if t.Cmds[i].Defer {
-				defer e.runDeferred(t, call, i, &deferredExitCode)
-				continue
+		// Deferred commands run once, after the last attempt, using the task
+		// as it was compiled for that attempt
+		var deferredExitCode uint8
+		var deferred []int
+		runTask, runCall := t, call
+		defer func() {
+			for _, i := range slices.Backward(deferred) {
+				e.runDeferred(ctx, runTask, runCall, i, &deferredExitCode)
 			}
+		}()
+
+		cmdsCtx := ctx
+		if t.Timeout > 0 {
+			var cancel context.CancelFunc
+			cmdsCtx, cancel = context.WithTimeoutCause(ctx, t.Timeout, &errors.TaskTimeoutError{
+				TaskName: t.Task,
+				Timeout:  t.Timeout,
+			})
+			defer cancel()
+		}
 
-			if err := e.runCommand(ctx, t, call, i); err != nil {
-				if err2 := e.statusOnError(t); err2 != nil {
-					e.Logger.VerboseErrf(logger.Yellow, "task: error cleaning status on error: %v\n", err2)
+		err = e.retry(cmdsCtx, t.Retries, t.Name(), func(attempt int) error {
+			deferredExitCode = 0
+			if attempt > 0 {
+				runCall = retryCall(call, attempt)
+				compiled, err := e.CompiledTask(runCall)
+				if err != nil {
+					return err
 				}
+				runTask = compiled
+			}
 
-				exitCode, isExitError := interp.IsExitStatus(err)
-				if isExitError {
-					if t.IgnoreError {
-						e.Logger.VerboseErrf(logger.Yellow, "task: task error ignored: %v\n", err)
-						continue
+			for i := range runTask.Cmds {
+				if runTask.Cmds[i].Defer {
+					if !slices.Contains(deferred, i) {
+						deferred = append(deferred, i)
 					}
-					deferredExitCode = exitCode
+					continue
 				}
 
-				if call.Indirect {
+				if err := cmdsCtx.Err(); err != nil {
 					return err
 				}
 
-				return &errors.TaskRunError{TaskName: t.Task, Err: err}
+				if err := e.runCommand(cmdsCtx, runTask, runCall, i); err != nil {
+					if err2 := e.statusOnError(runTask); err2 != nil {
+						e.Logger.VerboseErrf(logger.Yellow, "task: error cleaning status on error: %v\n", err2)
+					}
+
+					exitCode, isExitError := interp.IsExitStatus(err)
+					if isExitError {
+						if runTask.IgnoreError {
+							e.Logger.VerboseErrf(logger.Yellow, "task: task error ignored: %v\n", err)
+							continue
+						}
+						deferredExitCode = exitCode
+					}
+					return err
+				}
 			}
+			return nil
+		})
+		if err != nil {
+			err = contextError(cmdsCtx, err)
+			var timeoutErr *errors.TaskTimeoutError
+			var signalErr *errors.TaskCancelledBySignalError
+			if call.Indirect || errors.As(err, &timeoutErr) || errors.As(err, &signalErr) {
+				return err
+			}
+			return &errors.TaskRunError{TaskName: t.Task, Err: err}
+		}
+
+		if err := e.statusOnSuccess(runTask); err != nil {
+			e.Logger.VerboseErrf(logger.Yellow, "task: error recording status on success: %v\n", err)
+		}
+		if err := e.storeInCache(ctx, t, cacheKey); artifact.IsTimeout(err) {
+			e.Logger.Errf(logger.Yellow, "task: Remote cache timed out, %q was not stored: %v\n", t.Name(), err)
+		} else if err != nil {
+			e.Logger.VerboseErrf(logger.Yellow, "task: unable to store %q in cache: %v\n", t.Name(), err)
 		}
 		e.Logger.VerboseErrf(logger.Magenta, "task: %q finished\n", call.Task)
 		return nil
@@ -258,28 +427,65 @@ func (e *Executor) mkdir(t *ast.Task) error {
 	return nil
 }
 
-func (e *Executor) runDeps(ctx context.Context, t *ast.Task) error {
-	g, ctx := errgroup.WithContext(ctx)
+func (e *Executor) runDeps(ctx context.Context, t *ast.Task) (err error) {
+	if len(t.Deps) > 0 {
+		deps := make([]string, len(t.Deps))
+		for i, d := range t.Deps {
+			deps[i] = d.Task
+		}
+		var span *trace.Span
+		ctx, span = e.Tracer.Start(ctx, "deps", "deps", map[string]any{"deps": deps})
+		defer span.End()
+		start := time.Now()
+		e.events.Emit(events.Event{Type: events.DepsStarted, Task: t.Name(), Deps: deps})
+		defer func() {
+			e.events.EmitResult(events.Event{Type: events.DepsFinished, Task: t.Name(), Deps: deps}, start, err, events.DepsFailed)
+		}()
+	}
+
+	timing := timings.FromContext(ctx)
+	ctx = timings.WithDeps(ctx)
+
+	// By default, the first dep that fails cancels the others. When the deps
+	// are allowed to continue, every dep finishes and all of their errors are
+	// reported together.
+	cancel := t.DepsFailure == ast.DepsFailureCancel || (t.DepsFailure == "" && !e.KeepGoing)
+	g := &errgroup.Group{}
+	if cancel {
+		g, ctx = errgroup.WithContext(ctx)
+	}
+	errs := make([]error, len(t.Deps))
 
 	reacquire := e.releaseConcurrencyLimit()
-	defer reacquire()
+	defer timing.WaitFor(reacquire)
+
+	start := time.Now()
+	defer func() { timing.AddDeps(time.Since(start)) }()
 
-	for _, d := range t.Deps {
-		d := d
+	for i, d := range t.Deps {
 		g.Go(func() error {
 			err := e.RunTask(ctx, &Call{Task: d.Task, Vars: d.Vars, Silent: d.Silent, Indirect: true})
-			if err != nil {
+			if cancel {
 				return err
 			}
+			errs[i] = err
 			return nil
 		})
 	}
 
-	return g.Wait()
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	names := make([]string, len(t.Deps))
+	for i, d := range t.Deps {
+		names[i] = d.Task
+	}
+	return joinTaskErrors(names, errs)
 }
 
-func (e *Executor) runDeferred(t *ast.Task, call *Call, i int, deferredExitCode *uint8) {
-	ctx, cancel := context.WithCancel(context.Background())
+func (e *Executor) runDeferred(ctx context.Context, t *ast.Task, call *Call, i int, deferredExitCode *uint8) {
+	// Deferred commands run even if the task was cancelled
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
 	defer cancel()
 
 	origTask, err := e.GetTask(call)
@@ -305,17 +511,47 @@ func (e *Executor) runDeferred(t *ast.Task, call *Call, i int, deferredExitCode
 
 func (e *Executor) runCommand(ctx context.Context, t *ast.Task, call *Call, i int) error {
 	cmd := t.Cmds[i]
+	if cmd.Retries == nil || e.Dry {
+		return e.runCommandAttempt(ctx, t, call, i, 0)
+	}
+
+	return e.retry(ctx, cmd.Retries, t.Name(), func(attempt int) error {
+		// Deferred commands have already been templated with the exit code
+		// of the task, so they are retried as they are
+		if attempt == 0 || cmd.Defer {
+			return e.runCommandAttempt(ctx, t, call, i, attempt)
+		}
+		retryCall := retryCall(call, attempt)
+		retryTask, err := e.CompiledTask(retryCall)
+		if err != nil {
+			return err
+		}
+		return e.runCommandAttempt(ctx, retryTask, retryCall, i, attempt)
+	})
+}
+
+// runCommandAttempt runs a single attempt of the command at the given index.
+// Calls to other tasks are given the attempt, so that they can tell whether
+// the command is being retried.
+func (e *Executor) runCommandAttempt(ctx context.Context, t *ast.Task, call *Call, i int, attempt int) error {
+	cmd := t.Cmds[i]
 
 	switch {
 	case cmd.Task != "":
 		reacquire := e.releaseConcurrencyLimit()
-		defer reacquire()
+		defer timings.FromContext(ctx).WaitFor(reacquire)
 
-		err := e.RunTask(ctx, &Call{Task: cmd.Task, Vars: cmd.Vars, Silent: cmd.Silent, Indirect: true})
-		if err != nil {
-			return err
+		if cmd.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeoutCause(ctx, cmd.Timeout, &errors.TaskTimeoutError{
+				TaskName: cmd.Task,
+				Timeout:  cmd.Timeout,
+			})
+			defer cancel()
 		}
-		return nil
+
+		err := e.RunTask(ctx, &Call{Task: cmd.Task, Vars: cmd.Vars, Silent: cmd.Silent, Indirect: true, RetryAttempt: attempt})
+		return contextError(ctx, err)
 	case cmd.Cmd != "":
 		if !shouldRunOnCurrentPlatform(cmd.Platforms) {
 			e.Logger.VerboseOutf(logger.Yellow, "task: [%s] %s not for current platform - ignored\n", t.Name(), cmd.Cmd)
@@ -330,7 +566,7 @@ func (e *Executor) runCommand(ctx context.Context, t *ast.Task, call *Call, i in
 			return nil
 		}
 
-		outputWrapper := e.Output
+		outputWrapper := junit.FromContext(ctx).Wrap(e.Output)
 		if t.Interactive {
 			outputWrapper = output.Interleaved{}
 		}
@@ -339,8 +575,23 @@ func (e *Executor) runCommand(ctx context.Context, t *ast.Task, call *Call, i in
 		if err != nil {
 			return fmt.Errorf("task: failed to get variables: %w", err)
 		}
-		stdOut, stdErr, closer := outputWrapper.WrapWriter(e.Stdout, e.Stderr, t.Prefix, outputTemplater)
-
+		stdOut, stdErr := e.taskOutput(ctx)
+		stdOut, stdErr, closer := outputWrapper.WrapWriter(stdOut, stdErr, t.Prefix, outputTemplater)
+
+		_, span := e.Tracer.Start(ctx, cmd.Cmd, "cmd", map[string]any{"task": t.Name(), "index": i})
+		defer span.End()
+		if cmd.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeoutCause(ctx, cmd.Timeout, &errors.TaskTimeoutError{
+				TaskName: t.Task,
+				Cmd:      cmd.Cmd,
+				Timeout:  cmd.Timeout,
+			})
+			defer cancel()
+		}
+		index := i
+		start := time.Now()
+		e.events.Emit(events.Event{Type: events.CmdStarted, Task: t.Name(), Index: &index, Cmd: cmd.Cmd})
 		err = execext.RunCommand(ctx, &execext.RunCommandOptions{
 			Command:   cmd.Cmd,
 			Dir:       t.Dir,
@@ -350,7 +601,10 @@ func (e *Executor) runCommand(ctx context.Context, t *ast.Task, call *Call, i in
 			Stdin:     e.Stdin,
 			Stdout:    stdOut,
 			Stderr:    stdErr,
+			// The grace period of the task applies to all of its commands
+			KillTimeout: cmp.Or(t.KillTimeout, e.KillTimeout),
 		})
+		e.events.EmitResult(events.Event{Type: events.CmdFinished, Task: t.Name(), Index: &index, Cmd: cmd.Cmd}, start, err, events.CmdFailed)
 		if closeErr := closer(err); closeErr != nil {
 			e.Logger.Errf(logger.Red, "task: unable to close writer: %v\n", closeErr)
 		}
@@ -358,12 +612,72 @@ func (e *Executor) runCommand(ctx context.Context, t *ast.Task, call *Call, i in
 			e.Logger.VerboseErrf(logger.Yellow, "task: [%s] command error ignored: %v\n", t.Name(), err)
 			return nil
 		}
+		err = contextError(ctx, err)
+		if annotator, ok := e.Output.(output.Annotator); ok && err != nil && !t.Interactive {
+			annotator.Annotate(e.Stdout, t, cmd.Cmd, err)
+		}
 		return err
 	default:
 		return nil
 	}
 }
 
+type taskOutputKey struct{}
+
+type taskOutput struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// wrapTaskOutput wraps the output of all the commands of the given task if the
+// output style groups it by task. The returned context carries the writers the
+// commands should write to and must be passed to them.
+func (e *Executor) wrapTaskOutput(ctx context.Context, t *ast.Task, call *Call) (context.Context, output.CloseFunc) {
+	wrapper, ok := e.Output.(output.TaskWrapper)
+	if !ok {
+		return ctx, func(error) error { return nil }
+	}
+	// Interactive tasks need to write to the terminal right away, even if
+	// they were called by another task
+	if t.Interactive {
+		resume := e.pauseOutput()
+		return context.WithValue(ctx, taskOutputKey{}, &taskOutput{e.Stdout, e.Stderr}), func(error) error {
+			resume()
+			return nil
+		}
+	}
+	stdOut, stdErr, closer := wrapper.WrapTask(e.Stdout, e.Stderr, t, call.Vars)
+	return context.WithValue(ctx, taskOutputKey{}, &taskOutput{stdOut, stdErr}), closer
+}
+
+// taskOutput returns the writers that the commands running in ctx write to.
+func (e *Executor) taskOutput(ctx context.Context) (io.Writer, io.Writer) {
+	if o, ok := ctx.Value(taskOutputKey{}).(*taskOutput); ok {
+		return o.stdout, o.stderr
+	}
+	return e.Stdout, e.Stderr
+}
+
+// contextError returns the error that explains why the given context was
+// cancelled in place of the given error: a [errors.TaskTimeoutError] if a
+// timeout was exceeded or a [errors.TaskCancelledBySignalError] if Task
+// received a signal before the command could finish.
+func contextError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	cause := context.Cause(ctx)
+	var timeoutErr *errors.TaskTimeoutError
+	if errors.As(cause, &timeoutErr) {
+		return cause
+	}
+	var signalErr *errors.TaskCancelledBySignalError
+	if errors.As(cause, &signalErr) && errors.Is(err, context.Canceled) {
+		return cause
+	}
+	return err
+}
+
 func (e *Executor) startExecution(ctx context.Context, t *ast.Task, execute func(ctx context.Context) error) error {
 	h, err := e.GetHash(t)
 	if err != nil {
@@ -379,6 +693,7 @@ func (e *Executor) startExecution(ctx context.Context, t *ast.Task, execute func
 	if otherExecutionCtx, ok := e.executionHashes[h]; ok {
 		e.executionHashesMutex.Unlock()
 		e.Logger.VerboseErrf(logger.Magenta, "task: skipping execution of task: %s\n", h)
+		e.events.Emit(events.Event{Type: events.TaskDeduplicated, Task: t.Name()})
 
 		// Release our execution slot to avoid blocking other tasks while we wait
 		reacquire := e.releaseConcurrencyLimit()
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"cmp"
 	"context"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"io/fs"
@@ -22,11 +24,18 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"mvdan.cc/sh/v3/interp"
 
 	"github.com/go-task/task/v3"
 	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/events"
 	"github.com/go-task/task/v3/internal/experiments"
 	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/lint"
+	"github.com/go-task/task/v3/internal/output"
+	"github.com/go-task/task/v3/internal/report"
+	"github.com/go-task/task/v3/internal/trace"
+	"github.com/go-task/task/v3/taskfile"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
 
@@ -566,8 +575,8 @@ func TestStatusChecksum(t *testing.T) { // nolint:paralleltest // cannot run in
 		files []string
 		task  string
 	}{
-		{[]string{"generated.txt", ".task/checksum/build"}, "build"},
-		{[]string{"generated.txt", ".task/checksum/build-with-status"}, "build-with-status"},
+		{[]string{"generated.txt", ".task/fingerprints.json"}, "build"},
+		{[]string{"generated.txt", ".task/fingerprints.json"}, "build-with-status"},
 	}
 
 	for _, test := range tests { // nolint:paralleltest // cannot run in parallel
@@ -598,9 +607,9 @@ func TestStatusChecksum(t *testing.T) { // nolint:paralleltest // cannot run in
 				require.NoError(t, err)
 			}
 
-			// Capture the modification time, so we can ensure the checksum file
-			// is not regenerated when the hash hasn't changed.
-			s, err := os.Stat(filepathext.SmartJoin(tempDir.Fingerprint, "checksum/"+test.task))
+			// Capture the modification time, so we can ensure the fingerprint
+			// store is not rewritten when the hash hasn't changed.
+			s, err := os.Stat(filepathext.SmartJoin(tempDir.Fingerprint, "fingerprints.json"))
 			require.NoError(t, err)
 			time := s.ModTime()
 
@@ -608,13 +617,603 @@ func TestStatusChecksum(t *testing.T) { // nolint:paralleltest // cannot run in
 			require.NoError(t, e.Run(context.Background(), &task.Call{Task: test.task}))
 			assert.Equal(t, `task: Task "`+test.task+`" is up to date`+"\n", buff.String())
 
-			s, err = os.Stat(filepathext.SmartJoin(tempDir.Fingerprint, "checksum/"+test.task))
+			s, err = os.Stat(filepathext.SmartJoin(tempDir.Fingerprint, "fingerprints.json"))
 			require.NoError(t, err)
 			assert.Equal(t, time, s.ModTime())
 		})
 	}
 }
 
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.ExecutorWithDir("testdata/explain"),
+		task.ExecutorWithStdout(&buff),
+		task.ExecutorWithStderr(&buff),
+	)
+	require.NoError(t, e.Setup())
+
+	require.NoError(t, e.Explain(context.Background(),
+		&task.Call{Task: "up-to-date"},
+		&task.Call{Task: "not-up-to-date"},
+		&task.Call{Task: "always"},
+	))
+	assert.Equal(t, strings.Join([]string{
+		`task: Task "up-to-date" is up to date:`,
+		`  - status command "test -f Taskfile.yml" succeeded`,
+		`task: Task "not-up-to-date" is not up to date:`,
+		`  - status command "test -f Taskfile.yml" succeeded`,
+		`  - status command "test -f missing.txt" failed: exit status 1`,
+		`task: Task "always" is not up to date:`,
+		`  - the task has no status or sources, so it always runs`,
+		``,
+	}, "\n"), buff.String())
+	assert.NoFileExists(t, "testdata/explain/missing.txt")
+}
+
+func TestArtifactCache(t *testing.T) { // nolint:paralleltest // cannot run in parallel
+	const dir = "testdata/artifact_cache"
+
+	for _, f := range []string{"generated.txt", "runs.txt", ".task"} {
+		require.NoError(t, os.RemoveAll(filepathext.SmartJoin(dir, f)))
+	}
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.ExecutorWithDir(dir),
+		task.ExecutorWithStdout(&buff),
+		task.ExecutorWithStderr(&buff),
+		task.ExecutorWithTempDir(task.TempDir{
+			Remote:      filepathext.SmartJoin(dir, ".task"),
+			Fingerprint: filepathext.SmartJoin(dir, ".task"),
+		}),
+		task.ExecutorWithCache(true),
+	)
+	require.NoError(t, e.Setup())
+
+	run := func(source string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepathext.SmartJoin(dir, "source.txt"), []byte(source), 0o644))
+		buff.Reset()
+		require.NoError(t, e.Run(context.Background(), &task.Call{Task: "build"}))
+		b, err := os.ReadFile(filepathext.SmartJoin(dir, "generated.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, source, string(b))
+	}
+	runs := func() int {
+		t.Helper()
+		b, err := os.ReadFile(filepathext.SmartJoin(dir, "runs.txt"))
+		require.NoError(t, err)
+		return strings.Count(string(b), "ran")
+	}
+
+	run("one")
+	assert.Equal(t, 1, runs())
+	run("two")
+	assert.Equal(t, 2, runs())
+
+	// Switching back to a previously built source restores the outputs
+	run("one")
+	assert.Equal(t, 2, runs())
+	assert.Contains(t, buff.String(), `task: Task "build" restored from cache`)
+
+	// Once restored, the task is up to date
+	run("one")
+	assert.Equal(t, 2, runs())
+	assert.Contains(t, buff.String(), `task: Task "build" is up to date`)
+}
+
+func TestFingerprint(t *testing.T) { // nolint:paralleltest // cannot run in parallel
+	const dir = "testdata/fingerprint"
+
+	for _, f := range []string{"generated.txt", "runs.txt", ".task"} {
+		require.NoError(t, os.RemoveAll(filepathext.SmartJoin(dir, f)))
+	}
+	require.NoError(t, os.WriteFile(filepathext.SmartJoin(dir, "source.txt"), []byte("source"), 0o644))
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.ExecutorWithDir(dir),
+		task.ExecutorWithStdout(&buff),
+		task.ExecutorWithStderr(&buff),
+		task.ExecutorWithTempDir(task.TempDir{
+			Remote:      filepathext.SmartJoin(dir, ".task"),
+			Fingerprint: filepathext.SmartJoin(dir, ".task"),
+		}),
+	)
+	require.NoError(t, e.Setup())
+
+	run := func(version string) {
+		t.Helper()
+		vars := ast.NewVars()
+		vars.Set("VERSION", ast.Var{Value: version})
+		require.NoError(t, e.Run(context.Background(), &task.Call{Task: "build", Vars: vars}))
+	}
+	runs := func() int {
+		t.Helper()
+		b, err := os.ReadFile(filepathext.SmartJoin(dir, "runs.txt"))
+		require.NoError(t, err)
+		return strings.Count(string(b), "ran")
+	}
+
+	run("1.0")
+	assert.Equal(t, 1, runs())
+	run("1.0")
+	assert.Equal(t, 1, runs())
+
+	// Changing a fingerprinted variable makes the task run again
+	run("2.0")
+	assert.Equal(t, 2, runs())
+	run("2.0")
+	assert.Equal(t, 2, runs())
+
+	// So does tampering with the generated files
+	require.NoError(t, os.WriteFile(filepathext.SmartJoin(dir, "generated.txt"), []byte("tampered"), 0o644))
+	run("2.0")
+	assert.Equal(t, 3, runs())
+	b, err := os.ReadFile(filepathext.SmartJoin(dir, "generated.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "source", string(b))
+}
+
+func TestFingerprintStore(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/fingerprint_store"
+	tempDir := t.TempDir()
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.ExecutorWithDir(dir),
+		task.ExecutorWithStdout(&buff),
+		task.ExecutorWithStderr(&buff),
+		task.ExecutorWithTempDir(task.TempDir{
+			Remote:      tempDir,
+			Fingerprint: tempDir,
+		}),
+	)
+	require.NoError(t, e.Setup())
+	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "default"}))
+
+	list := func() []string {
+		t.Helper()
+		buff.Reset()
+		require.NoError(t, e.ListFingerprints())
+		var keys []string
+		for _, line := range strings.Split(strings.TrimSpace(buff.String()), "\n") {
+			keys = append(keys, strings.Join(strings.Fields(line)[:2], " "))
+		}
+		return keys
+	}
+
+	// Each call of the task with different variables has its own fingerprint,
+	// which shows a hash of their values instead of the values
+	keys := list()
+	require.Len(t, keys, 3)
+	assert.Equal(t, "build checksum", keys[0])
+	assert.Regexp(t, `^build OS=[0-9a-f]{8}$`, keys[1])
+	assert.Regexp(t, `^build OS=[0-9a-f]{8}$`, keys[2])
+	assert.NotEqual(t, keys[1], keys[2])
+	assert.NotContains(t, buff.String(), "linux")
+
+	buff.Reset()
+	require.NoError(t, e.ClearFingerprints("build"))
+	assert.Equal(t, "task: Removed 3 fingerprint(s) of task \"build\"\n", buff.String())
+
+	buff.Reset()
+	require.NoError(t, e.ListFingerprints())
+	assert.Equal(t, "task: No fingerprints stored\n", buff.String())
+
+	require.EqualError(t, e.ClearFingerprints("missing"), `task: Task "missing" does not exist`)
+}
+
+func TestEvents(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/events"
+
+	run := func(t *testing.T, name string) ([]events.Event, error) {
+		t.Helper()
+		var buff, stream SyncBuffer
+		e := task.NewExecutor(
+			task.ExecutorWithDir(dir),
+			task.ExecutorWithStdout(&buff),
+			task.ExecutorWithStderr(&buff),
+			task.ExecutorWithEvents(&stream),
+		)
+		require.NoError(t, e.Setup())
+		runErr := e.Run(context.Background(), &task.Call{Task: name})
+
+		var evs []events.Event
+		for _, line := range strings.Split(strings.TrimSpace(stream.buf.String()), "\n") {
+			var ev events.Event
+			require.NoError(t, json.Unmarshal([]byte(line), &ev))
+			evs = append(evs, ev)
+		}
+		return evs, runErr
+	}
+	types := func(evs []events.Event, task string) []events.Type {
+		var types []events.Type
+		for _, ev := range evs {
+			if ev.Task == task {
+				types = append(types, ev.Type)
+			}
+		}
+		return types
+	}
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		evs, err := run(t, "default")
+		require.NoError(t, err)
+		assert.Equal(t, []events.Type{
+			events.TaskStarted,
+			events.DepsStarted,
+			events.DepsFinished,
+			events.CmdStarted,
+			events.CmdFinished,
+			events.TaskFinished,
+		}, types(evs, "default"))
+		assert.Equal(t, []events.Type{
+			events.TaskStarted,
+			events.CmdStarted,
+			events.CmdFinished,
+			events.TaskFinished,
+			events.TaskDeduplicated,
+		}, types(evs, "dep"))
+	})
+
+	t.Run("precondition", func(t *testing.T) {
+		t.Parallel()
+
+		evs, err := run(t, "precondition")
+		require.Error(t, err)
+		assert.Equal(t, []events.Type{
+			events.TaskStarted,
+			events.PreconditionFailed,
+			events.TaskFailed,
+		}, types(evs, "precondition"))
+		assert.Equal(t, "never met", evs[1].Message)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		t.Parallel()
+
+		evs, err := run(t, "fail")
+		require.Error(t, err)
+		require.Len(t, evs, 4)
+		assert.Equal(t, events.CmdFailed, evs[2].Type)
+		require.NotNil(t, evs[2].ExitCode)
+		assert.Equal(t, 3, *evs[2].ExitCode)
+		assert.Equal(t, events.TaskFailed, evs[3].Type)
+		require.NotNil(t, evs[3].ExitCode)
+		assert.Equal(t, 3, *evs[3].ExitCode)
+	})
+}
+
+func TestJUnit(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/junit"
+
+	type testCase struct {
+		Name      string `xml:"name,attr"`
+		ClassName string `xml:"classname,attr"`
+		Skipped   *struct {
+			Message string `xml:"message,attr"`
+		} `xml:"skipped"`
+		Failure *struct {
+			Type string `xml:"type,attr"`
+		} `xml:"failure"`
+		SystemOut string `xml:"system-out"`
+		SystemErr string `xml:"system-err"`
+	}
+	run := func(t *testing.T, name string, style ast.Output) (map[string]testCase, error) {
+		t.Helper()
+		var buff, out SyncBuffer
+		e := task.NewExecutor(
+			task.ExecutorWithDir(dir),
+			task.ExecutorWithStdout(&buff),
+			task.ExecutorWithStderr(&buff),
+			task.ExecutorWithOutputStyle(style),
+			task.ExecutorWithJUnit(&out),
+		)
+		require.NoError(t, e.Setup())
+		runErr := e.Run(context.Background(), &task.Call{Task: name})
+
+		var report struct {
+			Suites []struct {
+				Cases []testCase `xml:"testcase"`
+			} `xml:"testsuite"`
+		}
+		require.NoError(t, xml.Unmarshal(out.buf.Bytes(), &report))
+		require.Len(t, report.Suites, 1)
+		cases := map[string]testCase{}
+		for _, c := range report.Suites[0].Cases {
+			cases[c.Name] = c
+		}
+		return cases, runErr
+	}
+
+	for _, style := range []ast.Output{
+		{Name: "interleaved"},
+		{Name: "group"},
+		{Name: "prefixed"},
+	} {
+		t.Run(style.Name, func(t *testing.T) {
+			t.Parallel()
+
+			cases, err := run(t, "default", style)
+			require.NoError(t, err)
+			require.Len(t, cases, 3)
+			assert.Equal(t, "default\n", cases["default"].SystemOut)
+			assert.Equal(t, "warning\n", cases["default"].SystemErr)
+			assert.Equal(t, "lib", cases["build"].ClassName)
+			assert.Equal(t, "build\n", cases["build"].SystemOut)
+			require.NotNil(t, cases["up-to-date"].Skipped)
+			assert.Equal(t, "up to date", cases["up-to-date"].Skipped.Message)
+		})
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		t.Parallel()
+
+		cases, err := run(t, "fail", ast.Output{})
+		require.Error(t, err)
+		require.NotNil(t, cases["fail"].Failure)
+		assert.Equal(t, "exit code 3", cases["fail"].Failure.Type)
+		assert.Equal(t, "failing\n", cases["fail"].SystemOut)
+	})
+}
+
+func TestTrace(t *testing.T) {
+	t.Parallel()
+
+	var buff SyncBuffer
+	tracer := trace.New()
+	e := task.NewExecutor(
+		task.ExecutorWithDir("testdata/trace"),
+		task.ExecutorWithStdout(&buff),
+		task.ExecutorWithStderr(&buff),
+		task.ExecutorWithTracer(tracer),
+	)
+	require.NoError(t, e.Setup())
+	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "default"}))
+
+	var out bytes.Buffer
+	require.NoError(t, tracer.Write(&out))
+	var f struct {
+		TraceEvents []struct {
+			Name string `json:"name"`
+			Cat  string `json:"cat"`
+		} `json:"traceEvents"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &f))
+
+	spans := map[string]string{}
+	for _, ev := range f.TraceEvents {
+		spans[ev.Name] = ev.Cat
+	}
+	assert.Equal(t, "task", spans["default"])
+	assert.Equal(t, "task", spans["dep"])
+	assert.Equal(t, "deps", spans["deps"])
+	assert.Equal(t, "fingerprint", spans["fingerprint"])
+	assert.Equal(t, "var", spans["printf hello"])
+	assert.Equal(t, "cmd", spans["echo hello"])
+	assert.Equal(t, "cmd", spans["echo dep"])
+}
+
+func TestTimings(t *testing.T) {
+	t.Parallel()
+
+	var buff SyncBuffer
+	e := task.NewExecutor(
+		task.ExecutorWithDir("testdata/trace"),
+		task.ExecutorWithStdout(&buff),
+		task.ExecutorWithStderr(&buff),
+		task.ExecutorWithTimings(true),
+	)
+	require.NoError(t, e.Setup())
+	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "default"}))
+
+	out := buff.buf.String()
+	assert.Regexp(t, `(?m)^TASK\s+WALL\s+WAIT\s+DEPS\s+SELF$`, out)
+	assert.Regexp(t, `(?m)^default\s+\d`, out)
+	assert.Regexp(t, `(?m)^dep\s+\d`, out)
+	assert.Regexp(t, `Critical path: default \(\S+\) -> dep \(\S+\)`, out)
+}
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/report"
+
+	type reportTask struct {
+		Task     string `json:"task"`
+		Outcome  string `json:"outcome"`
+		ExitCode *int   `json:"exit_code"`
+	}
+	run := func(t *testing.T, name string, style ast.Output) ([]reportTask, error) {
+		t.Helper()
+		var buff, out SyncBuffer
+		e := task.NewExecutor(
+			task.ExecutorWithDir(dir),
+			task.ExecutorWithStdout(&buff),
+			task.ExecutorWithStderr(&buff),
+			task.ExecutorWithOutputStyle(style),
+			task.ExecutorWithReport(report.FormatJSON, &out),
+		)
+		require.NoError(t, e.Setup())
+		runErr := e.Run(context.Background(), &task.Call{Task: name})
+
+		var r struct {
+			Tasks []reportTask `json:"tasks"`
+		}
+		require.NoError(t, json.Unmarshal(out.buf.Bytes(), &r))
+		return r.Tasks, runErr
+	}
+	outcomes := func(tasks []reportTask) map[string][]string {
+		outcomes := map[string][]string{}
+		for _, t := range tasks {
+			outcomes[t.Task] = append(outcomes[t.Task], t.Outcome)
+		}
+		return outcomes
+	}
+
+	for _, style := range []ast.Output{
+		{Name: "interleaved"},
+		{Name: "group"},
+		{Name: "prefixed"},
+	} {
+		t.Run(style.Name, func(t *testing.T) {
+			t.Parallel()
+
+			tasks, err := run(t, "default", style)
+			require.NoError(t, err)
+			assert.Equal(t, map[string][]string{
+				"default":        {"ran"},
+				"once":           {"ran", "deduplicated"},
+				"up-to-date":     {"up-to-date"},
+				"other-platform": {"skipped"},
+			}, outcomes(tasks))
+		})
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		t.Parallel()
+
+		tasks, err := run(t, "fail", ast.Output{})
+		require.Error(t, err)
+		require.Len(t, tasks, 2)
+		assert.Equal(t, "fail", tasks[1].Task)
+		assert.Equal(t, "failed", tasks[1].Outcome)
+		require.NotNil(t, tasks[1].ExitCode)
+		assert.Equal(t, 3, *tasks[1].ExitCode)
+	})
+}
+
+func TestUnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.ExecutorWithDir("testdata/unknown_keys"),
+		task.ExecutorWithStdout(&buff),
+		task.ExecutorWithStderr(&buff),
+	)
+	require.NoError(t, e.Setup())
+	assert.Contains(t, buff.String(), `task: Unknown key "sorces" at `)
+	assert.Contains(t, buff.String(), `Taskfile.yml:5:5, did you mean "sources"?`)
+
+	e = task.NewExecutor(
+		task.ExecutorWithDir("testdata/unknown_keys"),
+		task.ExecutorWithStdout(io.Discard),
+		task.ExecutorWithStderr(io.Discard),
+		task.ExecutorWithStrict(true),
+	)
+	err := e.Setup()
+	require.ErrorIs(t, err, taskfile.ErrUnknownKey)
+	var decodeErr *errors.TaskfileDecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, `unknown key "sorces", did you mean "sources"?`, decodeErr.Message)
+	assert.Equal(t, 5, decodeErr.Line)
+	assert.Equal(t, 5, decodeErr.Column)
+}
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	e := task.NewExecutor(
+		task.ExecutorWithDir("testdata/lint"),
+		task.ExecutorWithStdout(io.Discard),
+		task.ExecutorWithStderr(io.Discard),
+	)
+	require.NoError(t, e.Setup())
+
+	issues := e.Lint()
+	var got []string
+	for _, issue := range issues {
+		got = append(got, fmt.Sprintf("%d:%d %s %s %s", issue.Line, issue.Column, issue.Severity, issue.Rule, issue.Task))
+	}
+	assert.Equal(t, []string{
+		"5:19 error missing-task default",
+		"7:15 warning missing-required-var default",
+		"10:3 warning generates-without-sources build",
+		"12:13 error invalid-method build",
+		"19:15 error duplicate-alias test",
+		"20:10 error invalid-run test",
+		"35:3 warning unused-internal cleanup",
+	}, got)
+
+	var lintErr *errors.TaskfileLintError
+	require.ErrorAs(t, lint.Err(issues), &lintErr)
+	assert.Equal(t, 4, lintErr.Errors)
+	assert.Equal(t, 3, lintErr.Warnings)
+}
+
+func TestLintInvalidPlatform(t *testing.T) {
+	t.Parallel()
+
+	e := task.NewExecutor(
+		task.ExecutorWithDir("testdata/lint/invalid_platform"),
+		task.ExecutorWithStdout(io.Discard),
+		task.ExecutorWithStderr(io.Discard),
+	)
+	issue, ok := lint.FromError(e.Setup())
+	require.True(t, ok)
+	assert.Equal(t, lint.RuleInvalidPlatform, issue.Rule)
+	assert.Equal(t, `invalid platform "plan10"`, issue.Message)
+	assert.Equal(t, 5, issue.Line)
+}
+
+func TestTaskGraph(t *testing.T) {
+	t.Parallel()
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.ExecutorWithDir("testdata/graph"),
+		task.ExecutorWithStdout(&buff),
+		task.ExecutorWithStderr(&buff),
+	)
+	require.NoError(t, e.Setup())
+
+	g, err := e.TaskGraph(&task.Call{Task: "default"})
+	require.NoError(t, err)
+
+	var nodes []string
+	for _, node := range g.Nodes {
+		nodes = append(nodes, node.Name)
+	}
+	assert.Equal(t, []string{"default", "lint", "build", "compile-linux", "compile-windows", "start:foo", "start:bar"}, nodes)
+
+	var edges []string
+	for _, edge := range g.Edges {
+		edges = append(edges, fmt.Sprintf("%s %s %s", edge.From, edge.Type, edge.To))
+	}
+	assert.Equal(t, []string{
+		"build dep compile-linux",
+		"build dep compile-windows",
+		"lint call build",
+		"default dep lint",
+		"default call build",
+		"default call start:foo",
+		"default call start:bar",
+	}, edges)
+
+	require.NoError(t, e.PrintTaskGraph("dot", &task.Call{Task: "build"}))
+	assert.Equal(t, `digraph tasks {
+  "build";
+  "compile-linux";
+  "compile-windows";
+  "build" -> "compile-linux";
+  "build" -> "compile-windows";
+}
+`, buff.String())
+}
+
 func TestAlias(t *testing.T) {
 	t.Parallel()
 
@@ -976,7 +1575,7 @@ func TestStatusVariables(t *testing.T) {
 	require.NoError(t, e.Setup())
 	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "build-checksum"}))
 
-	assert.Contains(t, buff.String(), "3e464c4b03f4b65d740e1e130d4d108a")
+	assert.Contains(t, buff.String(), "72bee7b3583dcb98e698705be55b3d86")
 
 	buff.Reset()
 	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "build-ts"}))
@@ -1012,7 +1611,7 @@ func TestCmdsVariables(t *testing.T) {
 	require.NoError(t, e.Setup())
 	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "build-checksum"}))
 
-	assert.Contains(t, buff.String(), "3e464c4b03f4b65d740e1e130d4d108a")
+	assert.Contains(t, buff.String(), "72bee7b3583dcb98e698705be55b3d86")
 
 	buff.Reset()
 	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "build-ts"}))
@@ -1036,7 +1635,24 @@ func TestCyclicDep(t *testing.T) {
 		task.ExecutorWithStderr(io.Discard),
 	)
 	require.NoError(t, e.Setup())
-	assert.IsType(t, &errors.TaskCalledTooManyTimesError{}, e.Run(context.Background(), &task.Call{Task: "task-1"}))
+
+	err := e.Run(context.Background(), &task.Call{Task: "task-1"})
+	var cycleErr *errors.TaskCycleError
+	require.ErrorAs(t, err, &cycleErr)
+	assert.Equal(t, []string{"task-1", "task-2", "task-1"}, cycleErr.Cycle)
+	assert.Equal(t, errors.CodeTaskCycle, cycleErr.Code())
+	assert.Contains(t, err.Error(), "task: Cycle detected between tasks: task-1 -> task-2 -> task-1")
+	assert.Regexp(t, `task-2: .*Taskfile.yml:8:3`, err.Error())
+
+	err = e.Run(context.Background(), &task.Call{Task: "task-4"})
+	require.ErrorAs(t, err, &cycleErr)
+	assert.Equal(t, []string{"task-4", "task-5", "task-3", "task-4"}, cycleErr.Cycle)
+
+	assert.IsType(t, &errors.TaskCalledTooManyTimesError{}, e.Run(context.Background(), &task.Call{Task: "task-6"}))
+
+	vars := ast.NewVars()
+	vars.Set("N", ast.Var{Value: "3"})
+	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "countdown", Vars: vars}))
 }
 
 func TestTaskVersion(t *testing.T) {
@@ -1138,15 +1754,15 @@ func TestDry(t *testing.T) {
 	}
 }
 
-// TestDryChecksum tests if the checksum file is not being written to disk
+// TestDryChecksum tests if the fingerprint store is not being written to disk
 // if the dry mode is enabled.
 func TestDryChecksum(t *testing.T) {
 	t.Parallel()
 
 	const dir = "testdata/dry_checksum"
 
-	checksumFile := filepathext.SmartJoin(dir, ".task/checksum/default")
-	_ = os.Remove(checksumFile)
+	storeFile := filepathext.SmartJoin(dir, ".task/fingerprints.json")
+	_ = os.Remove(storeFile)
 
 	e := task.NewExecutor(
 		task.ExecutorWithDir(dir),
@@ -1161,13 +1777,13 @@ func TestDryChecksum(t *testing.T) {
 	require.NoError(t, e.Setup())
 	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "default"}))
 
-	_, err := os.Stat(checksumFile)
-	require.Error(t, err, "checksum file should not exist")
+	_, err := os.Stat(storeFile)
+	require.Error(t, err, "fingerprint store should not exist")
 
 	e.Dry = false
 	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "default"}))
-	_, err = os.Stat(checksumFile)
-	require.NoError(t, err, "checksum file should exist")
+	_, err = os.Stat(storeFile)
+	require.NoError(t, err, "fingerprint store should exist")
 }
 
 func TestIncludes(t *testing.T) {
@@ -2325,6 +2941,239 @@ task-1 ran successfully
 	assert.Contains(t, buff.String(), expectedOutputOrder)
 }
 
+func TestRetries(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/retries"
+
+	tests := []struct {
+		task     string
+		wantErr  bool
+		expected []string
+	}{
+		{
+			task: "task-retries",
+			expected: []string{
+				"attempt 0\n",
+				"task: [task-retries] attempt 1 of 3 failed with exit code 3, retrying in 0s\n",
+				"attempt 1\n",
+				"task: [task-retries] attempt 2 of 3 failed with exit code 3, retrying in 0s\n",
+				"attempt 2\n",
+				"deferred ran with exit code \n",
+			},
+		},
+		{
+			task:    "task-retries-exhausted",
+			wantErr: true,
+			expected: []string{
+				"attempt 0\n",
+				"task: [task-retries-exhausted] attempt 1 of 2 failed with exit code 3, retrying in 0s\n",
+				"attempt 1\n",
+				"task: [task-retries-exhausted] attempt 2 of 2 failed with exit code 3\n",
+				"deferred ran with exit code 3\n",
+			},
+		},
+		{
+			task: "cmd-retries",
+			expected: []string{
+				"first cmd ran\n",
+				"attempt 0\n",
+				"task: [cmd-retries] attempt 1 of 4 failed with exit code 3, retrying in 1ms\n",
+				"attempt 1\n",
+			},
+		},
+		{
+			task:     "cmd-retries-exit-codes",
+			wantErr:  true,
+			expected: []string{"attempt 0\n"},
+		},
+		{
+			task: "task-call-retries",
+			expected: []string{
+				"called attempt 0\n",
+				"task: [task-call-retries] attempt 1 of 2 failed with exit code 3, retrying in 0s\n",
+				"called attempt 1\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.task, func(t *testing.T) {
+			t.Parallel()
+
+			var buff bytes.Buffer
+			e := task.NewExecutor(
+				task.ExecutorWithDir(dir),
+				task.ExecutorWithStdout(&buff),
+				task.ExecutorWithStderr(&buff),
+				task.ExecutorWithSilent(true),
+			)
+			require.NoError(t, e.Setup())
+
+			err := e.Run(context.Background(), &task.Call{Task: test.task})
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, strings.Join(test.expected, ""), buff.String())
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/timeout"
+
+	tests := []struct {
+		task     string
+		expected *errors.TaskTimeoutError
+		output   string
+	}{
+		{
+			task:     "task-timeout",
+			expected: &errors.TaskTimeoutError{TaskName: "task-timeout", Timeout: 100 * time.Millisecond},
+			output:   "deferred ran\n",
+		},
+		{
+			task:     "cmd-timeout",
+			expected: &errors.TaskTimeoutError{TaskName: "cmd-timeout", Cmd: "sleep 10", Timeout: 100 * time.Millisecond},
+		},
+		{
+			task:     "process-group",
+			expected: &errors.TaskTimeoutError{TaskName: "process-group", Cmd: "sh -c 'sleep 10 & wait'", Timeout: 100 * time.Millisecond},
+		},
+		{
+			task:     "called-timeout",
+			expected: &errors.TaskTimeoutError{TaskName: "called-timeout", Timeout: 100 * time.Millisecond},
+		},
+		{
+			task:     "call-timeout",
+			expected: &errors.TaskTimeoutError{TaskName: "sleep", Timeout: 100 * time.Millisecond},
+		},
+		{
+			task:   "no-timeout",
+			output: "finished in time\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.task, func(t *testing.T) {
+			t.Parallel()
+
+			var buff bytes.Buffer
+			e := task.NewExecutor(
+				task.ExecutorWithDir(dir),
+				task.ExecutorWithStdout(&buff),
+				task.ExecutorWithStderr(&buff),
+				task.ExecutorWithSilent(true),
+			)
+			require.NoError(t, e.Setup())
+
+			start := time.Now()
+			err := e.Run(context.Background(), &task.Call{Task: test.task})
+			assert.Less(t, time.Since(start), 5*time.Second)
+			assert.Equal(t, test.output, buff.String())
+			if test.expected == nil {
+				require.NoError(t, err)
+				return
+			}
+			var timeoutErr *errors.TaskTimeoutError
+			require.ErrorAs(t, err, &timeoutErr)
+			assert.Equal(t, test.expected, timeoutErr)
+			assert.Equal(t, errors.CodeTaskTimeout, timeoutErr.Code())
+		})
+	}
+}
+
+func TestKeepGoing(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/keep_going"
+
+	runErr := func(name string, code int) error {
+		return &errors.TaskRunError{TaskName: name, Err: interp.NewExitStatus(uint8(code))}
+	}
+
+	tests := []struct {
+		name      string
+		keepGoing bool
+		calls     []string
+		expected  error
+		output    string
+	}{
+		{
+			name:     "deps are cancelled by default",
+			calls:    []string{"cancel"},
+			expected: interp.NewExitStatus(1),
+		},
+		{
+			name:      "deps keep going",
+			keepGoing: true,
+			calls:     []string{"default"},
+			expected:  &errors.TasksFailedError{Errors: []error{runErr("fail-1", 1), runErr("fail-2", 2)}},
+			output:    "slow finished\n",
+		},
+		{
+			name:     "deps_failure continue",
+			calls:    []string{"continue"},
+			expected: &errors.TasksFailedError{Errors: []error{runErr("fail-1", 1), runErr("fail-2", 2)}},
+			output:   "slow finished\n",
+		},
+		{
+			name:      "deps_failure cancel overrides keep going",
+			keepGoing: true,
+			calls:     []string{"cancel"},
+			expected:  interp.NewExitStatus(1),
+		},
+		{
+			name:     "nested errors are flattened",
+			calls:    []string{"nested"},
+			expected: &errors.TasksFailedError{Errors: []error{runErr("fail-1", 1), runErr("fail-2", 2), runErr("fail-3", 3)}},
+			output:   "slow finished\n",
+		},
+		{
+			name:      "calls keep going",
+			keepGoing: true,
+			calls:     []string{"fail-1", "ok", "fail-2"},
+			expected:  &errors.TasksFailedError{Errors: []error{runErr("fail-1", 1), runErr("fail-2", 2)}},
+			output:    "ok ran\n",
+		},
+		{
+			name:      "single failure is not combined",
+			keepGoing: true,
+			calls:     []string{"fail-1", "ok"},
+			expected:  runErr("fail-1", 1),
+			output:    "ok ran\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buff bytes.Buffer
+			e := task.NewExecutor(
+				task.ExecutorWithDir(dir),
+				task.ExecutorWithStdout(&buff),
+				task.ExecutorWithStderr(&buff),
+				task.ExecutorWithSilent(true),
+				task.ExecutorWithKeepGoing(test.keepGoing),
+			)
+			require.NoError(t, e.Setup())
+
+			calls := make([]*task.Call, len(test.calls))
+			for i, name := range test.calls {
+				calls[i] = &task.Call{Task: name}
+			}
+			err := e.Run(context.Background(), calls...)
+			assert.Equal(t, test.expected, err)
+			assert.Equal(t, test.output, buff.String())
+		})
+	}
+}
+
 func TestExitCodeZero(t *testing.T) {
 	t.Parallel()
 
@@ -2450,6 +3299,125 @@ func TestOutputGroupErrorOnlyShowsOutputOnFailure(t *testing.T) {
 	assert.NotContains(t, "passing", strings.TrimSpace(buff.String()))
 }
 
+func TestOutputCI(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_WORKSPACE", "")
+
+	const dir = "testdata/output_ci"
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.ExecutorWithDir(dir),
+		task.ExecutorWithStdout(&buff),
+		task.ExecutorWithStderr(&buff),
+	)
+	require.NoError(t, e.Setup())
+
+	expectedOutputOrder := strings.TrimSpace(`
+task: [hello] echo 'Hello!'
+::group::hello
+Hello!
+::endgroup::
+task: [bye] echo 'Bye!'
+task: [bye] echo 'See you!'
+::group::bye
+Bye!
+See you!
+::endgroup::
+`)
+	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "bye"}))
+	assert.Equal(t, expectedOutputOrder, strings.TrimSpace(buff.String()))
+
+	buff.Reset()
+	require.Error(t, e.Run(context.Background(), &task.Call{Task: "fail"}))
+	taskfile, err := filepath.Abs(filepath.Join(dir, "Taskfile.yml"))
+	require.NoError(t, err)
+	expectedOutputOrder = strings.TrimSpace(fmt.Sprintf(`
+task: [fail] echo 'Failing'
+task: [fail] exit 1
+::error title=task%%3A fail,file=%s,line=17,col=3::Command "exit 1" failed: exit status 1
+::group::fail
+Failing
+::endgroup::
+`, strings.ReplaceAll(taskfile, ":", "%3A")))
+	assert.Equal(t, expectedOutputOrder, strings.TrimSpace(buff.String()))
+}
+
+func TestOutputPrefixedStderr(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+	e := task.NewExecutor(
+		task.ExecutorWithDir("testdata/output_prefixed"),
+		task.ExecutorWithStdout(&stdout),
+		task.ExecutorWithStderr(&stderr),
+		task.ExecutorWithSilent(true),
+	)
+	require.NoError(t, e.Setup())
+	assert.True(t, e.OutputStyle.Prefixed.ColorStderr)
+
+	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "default"}))
+	assert.Equal(t, "[default] to stdout\n", stdout.String())
+	assert.Equal(t, "[default] to stderr\n", stderr.String())
+}
+
+func TestOutputTimestamps(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/output_group"
+
+	e := task.NewExecutor(
+		task.ExecutorWithDir(dir),
+		task.ExecutorWithStdout(io.Discard),
+		task.ExecutorWithStderr(io.Discard),
+		task.ExecutorWithTimestamps(output.TimestampsWall),
+	)
+	require.EqualError(t, e.Setup(), `task: output style "group" does not support timestamps`)
+
+	var buff bytes.Buffer
+	e = task.NewExecutor(
+		task.ExecutorWithDir(dir),
+		task.ExecutorWithStdout(&buff),
+		task.ExecutorWithStderr(&buff),
+		task.ExecutorWithSilent(true),
+		task.ExecutorWithOutputStyle(ast.Output{Name: "prefixed"}),
+		task.ExecutorWithTimestamps(output.TimestampsElapsed),
+	)
+	require.NoError(t, e.Setup())
+	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "bye"}))
+	assert.Regexp(t, `^ +\d+\.\d{3}s \[hello\] Hello!\n +\d+\.\d{3}s \[bye\] Bye!\n$`, buff.String())
+}
+
+func TestOutputFiles(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/output_files"
+	logs := t.TempDir()
+	var buff SyncBuffer
+	e := task.NewExecutor(
+		task.ExecutorWithDir(dir),
+		task.ExecutorWithStdout(&buff),
+		task.ExecutorWithStderr(&buff),
+		task.ExecutorWithSilent(true),
+		task.ExecutorWithOutputStyle(ast.Output{Name: "files", Files: ast.OutputFiles{Dir: logs}}),
+	)
+	require.NoError(t, e.Setup())
+
+	require.NoError(t, e.Run(context.Background(), &task.Call{Task: "default"}))
+	assert.Empty(t, buff.buf.String())
+	for name, expected := range map[string]string{
+		"default.log":        "default\n",
+		"print_TEXT=foo.log": "foo\nfoo on stderr\n",
+		"print_TEXT=bar.log": "bar\nbar on stderr\n",
+	} {
+		content, err := os.ReadFile(filepath.Join(logs, name))
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(content))
+	}
+
+	require.Error(t, e.Run(context.Background(), &task.Call{Task: "fail"}))
+	assert.Equal(t, fmt.Sprintf("task: [fail] output written to %s\n", filepath.Join(logs, "fail.log")), buff.buf.String())
+}
+
 func TestIncludedVars(t *testing.T) {
 	t.Parallel()
 
@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"time"
+
 	"gopkg.in/yaml.v3"
 
 	"github.com/go-task/task/v3/errors"
@@ -19,6 +21,8 @@ type Cmd struct {
 	IgnoreError bool
 	Defer       bool
 	Platforms   []*Platform
+	Retries     *Retries
+	Timeout     time.Duration
 }
 
 func (c *Cmd) DeepCopy() *Cmd {
@@ -36,6 +40,8 @@ func (c *Cmd) DeepCopy() *Cmd {
 		IgnoreError: c.IgnoreError,
 		Defer:       c.Defer,
 		Platforms:   deepcopy.Slice(c.Platforms),
+		Retries:     c.Retries.DeepCopy(),
+		Timeout:     c.Timeout,
 	}
 }
 
@@ -62,6 +68,8 @@ func (c *Cmd) UnmarshalYAML(node *yaml.Node) error {
 			IgnoreError bool `yaml:"ignore_error"`
 			Defer       *Defer
 			Platforms   []*Platform
+			Retries     *Retries
+			Timeout     time.Duration
 		}
 		if err := node.Decode(&cmdStruct); err != nil {
 			return errors.NewTaskfileDecodeError(err, node)
@@ -93,6 +101,8 @@ func (c *Cmd) UnmarshalYAML(node *yaml.Node) error {
 			c.Vars = cmdStruct.Vars
 			c.For = cmdStruct.For
 			c.Silent = cmdStruct.Silent
+			c.Retries = cmdStruct.Retries
+			c.Timeout = cmdStruct.Timeout
 			return nil
 		}
 
@@ -105,6 +115,8 @@ func (c *Cmd) UnmarshalYAML(node *yaml.Node) error {
 			c.Shopt = cmdStruct.Shopt
 			c.IgnoreError = cmdStruct.IgnoreError
 			c.Platforms = cmdStruct.Platforms
+			c.Retries = cmdStruct.Retries
+			c.Timeout = cmdStruct.Timeout
 			return nil
 		}
 
@@ -0,0 +1,28 @@
+package ast
+
+import "github.com/go-task/task/v3/internal/deepcopy"
+
+// Fingerprint selects the inputs of a task, besides its sources, that decide
+// whether it is up to date. The commands of the task and the location of its
+// Taskfile are always part of the fingerprint.
+type Fingerprint struct {
+	// Vars are the names of the variables whose values are fingerprinted
+	Vars []string
+	// Env are the names of the environment variables whose values are
+	// fingerprinted
+	Env []string
+	// Generates tells whether the contents of the generated files are
+	// fingerprinted, so the task runs again if they are changed
+	Generates bool
+}
+
+func (f *Fingerprint) DeepCopy() *Fingerprint {
+	if f == nil {
+		return nil
+	}
+	return &Fingerprint{
+		Vars:      deepcopy.Slice(f.Vars),
+		Env:       deepcopy.Slice(f.Env),
+		Generates: f.Generates,
+	}
+}
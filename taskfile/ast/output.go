@@ -12,6 +12,10 @@ type Output struct {
 	Name string `yaml:"-"`
 	// Group specific style
 	Group OutputGroup
+	// Prefixed specific style
+	Prefixed OutputPrefixed
+	// Files specific style
+	Files OutputFiles
 }
 
 // IsSet returns true if and only if a custom output style is set.
@@ -32,17 +36,39 @@ func (s *Output) UnmarshalYAML(node *yaml.Node) error {
 
 	case yaml.MappingNode:
 		var tmp struct {
-			Group *OutputGroup
+			Group    *OutputGroup
+			Prefixed *OutputPrefixed
+			Files    *OutputFiles
 		}
 		if err := node.Decode(&tmp); err != nil {
 			return errors.NewTaskfileDecodeError(err, node)
 		}
-		if tmp.Group == nil {
-			return errors.NewTaskfileDecodeError(nil, node).WithMessage(`output style must have the "group" key when in mapping form`)
+		styles := 0
+		for _, set := range []bool{tmp.Group != nil, tmp.Prefixed != nil, tmp.Files != nil} {
+			if set {
+				styles++
+			}
 		}
-		*s = Output{
-			Name:  "group",
-			Group: *tmp.Group,
+		switch {
+		case styles > 1:
+			return errors.NewTaskfileDecodeError(nil, node).WithMessage(`output style can only have one of the "group", "prefixed" and "files" keys`)
+		case tmp.Group != nil:
+			*s = Output{
+				Name:  "group",
+				Group: *tmp.Group,
+			}
+		case tmp.Prefixed != nil:
+			*s = Output{
+				Name:     "prefixed",
+				Prefixed: *tmp.Prefixed,
+			}
+		case tmp.Files != nil:
+			*s = Output{
+				Name:  "files",
+				Files: *tmp.Files,
+			}
+		default:
+			return errors.NewTaskfileDecodeError(nil, node).WithMessage(`output style must have the "group", "prefixed" or "files" key when in mapping form`)
 		}
 		return nil
 	}
@@ -52,8 +78,9 @@ func (s *Output) UnmarshalYAML(node *yaml.Node) error {
 
 // OutputGroup is the style options specific to the Group style.
 type OutputGroup struct {
-	Begin, End string
-	ErrorOnly  bool `yaml:"error_only"`
+	Begin, End  string
+	ErrorOnly   bool `yaml:"error_only"`
+	ColorStderr bool `yaml:"color_stderr"`
 }
 
 // IsSet returns true if and only if a custom output style is set.
@@ -63,3 +90,30 @@ func (g *OutputGroup) IsSet() bool {
 	}
 	return g.Begin != "" || g.End != ""
 }
+
+// OutputPrefixed is the style options specific to the Prefixed style.
+type OutputPrefixed struct {
+	ColorStderr bool `yaml:"color_stderr"`
+}
+
+// IsSet returns true if and only if a custom output style is set.
+func (p *OutputPrefixed) IsSet() bool {
+	if p == nil {
+		return false
+	}
+	return p.ColorStderr
+}
+
+// OutputFiles is the style options specific to the Files style.
+type OutputFiles struct {
+	// Dir is the directory the log files are written to
+	Dir string
+}
+
+// IsSet returns true if and only if a custom directory is set.
+func (f *OutputFiles) IsSet() bool {
+	if f == nil {
+		return false
+	}
+	return f.Dir != ""
+}
@@ -0,0 +1,115 @@
+package ast
+
+import (
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/errors"
+)
+
+// Backoff strategies for the delay between retries
+const (
+	BackoffConstant    = "constant"
+	BackoffExponential = "exponential"
+)
+
+// Retries represents how a failing task or command is retried
+type Retries struct {
+	// Count is the number of times to retry after the first attempt fails
+	Count int
+	// Delay is the time to wait before the first retry
+	Delay time.Duration
+	// Backoff is how the delay grows between retries
+	Backoff string
+	// MaxDelay caps the delay between retries when using exponential backoff
+	MaxDelay time.Duration
+	// ExitCodes limits the retries to the given exit codes. If empty, any
+	// non-zero exit code is retried.
+	ExitCodes []int
+}
+
+func (r *Retries) DeepCopy() *Retries {
+	if r == nil {
+		return nil
+	}
+	return &Retries{
+		Count:     r.Count,
+		Delay:     r.Delay,
+		Backoff:   r.Backoff,
+		MaxDelay:  r.MaxDelay,
+		ExitCodes: slices.Clone(r.ExitCodes),
+	}
+}
+
+// ShouldRetry returns whether a command that failed with the given exit code
+// on the given attempt, starting at zero, should be retried.
+func (r *Retries) ShouldRetry(attempt int, exitCode int) bool {
+	if r == nil || attempt >= r.Count {
+		return false
+	}
+	return len(r.ExitCodes) == 0 || slices.Contains(r.ExitCodes, exitCode)
+}
+
+// DelayBefore returns the time to wait before the given retry, starting at one.
+func (r *Retries) DelayBefore(retry int) time.Duration {
+	if r == nil || r.Delay <= 0 {
+		return 0
+	}
+	delay := r.Delay
+	if r.Backoff == BackoffExponential {
+		for i := 1; i < retry && (r.MaxDelay <= 0 || delay < r.MaxDelay); i++ {
+			delay *= 2
+		}
+	}
+	if r.MaxDelay > 0 {
+		return min(delay, r.MaxDelay)
+	}
+	return delay
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface.
+func (r *Retries) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+
+	case yaml.ScalarNode:
+		var count int
+		if err := node.Decode(&count); err != nil {
+			return errors.NewTaskfileDecodeError(err, node)
+		}
+		if count < 0 {
+			return errors.NewTaskfileDecodeError(nil, node).WithMessage("retries can't be negative")
+		}
+		r.Count = count
+		return nil
+
+	case yaml.MappingNode:
+		var retries struct {
+			Count     int
+			Delay     time.Duration
+			Backoff   string
+			MaxDelay  time.Duration `yaml:"max_delay"`
+			ExitCodes []int         `yaml:"exit_codes"`
+		}
+		if err := node.Decode(&retries); err != nil {
+			return errors.NewTaskfileDecodeError(err, node)
+		}
+		if retries.Count < 0 {
+			return errors.NewTaskfileDecodeError(nil, node).WithMessage("retries can't be negative")
+		}
+		switch retries.Backoff {
+		case "", BackoffConstant, BackoffExponential:
+		default:
+			return errors.NewTaskfileDecodeError(nil, node).WithMessage("invalid backoff %q, must be %q or %q", retries.Backoff, BackoffConstant, BackoffExponential)
+		}
+		r.Count = retries.Count
+		r.Delay = retries.Delay
+		r.Backoff = retries.Backoff
+		r.MaxDelay = retries.MaxDelay
+		r.ExitCodes = retries.ExitCodes
+		return nil
+	}
+
+	return errors.NewTaskfileDecodeError(nil, node).WithTypeMessage("retries")
+}
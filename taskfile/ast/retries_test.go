@@ -0,0 +1,95 @@
+package ast_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+func TestRetriesParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		content  string
+		expected *ast.Retries
+		wantErr  bool
+	}{
+		{
+			content:  "3",
+			expected: &ast.Retries{Count: 3},
+		},
+		{
+			content: `
+count: 2
+delay: 1s
+backoff: exponential
+max_delay: 5s
+exit_codes: [1, 75]
+`,
+			expected: &ast.Retries{
+				Count:     2,
+				Delay:     time.Second,
+				Backoff:   ast.BackoffExponential,
+				MaxDelay:  5 * time.Second,
+				ExitCodes: []int{1, 75},
+			},
+		},
+		{
+			content: "-1",
+			wantErr: true,
+		},
+		{
+			content: "backoff: linear",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		var retries ast.Retries
+		err := yaml.Unmarshal([]byte(test.content), &retries)
+		if test.wantErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, &retries)
+	}
+}
+
+func TestRetriesDelayBefore(t *testing.T) {
+	t.Parallel()
+
+	constant := &ast.Retries{Count: 3, Delay: time.Second}
+	assert.Equal(t, time.Second, constant.DelayBefore(1))
+	assert.Equal(t, time.Second, constant.DelayBefore(3))
+
+	exponential := &ast.Retries{Count: 5, Delay: time.Second, Backoff: ast.BackoffExponential, MaxDelay: 5 * time.Second}
+	assert.Equal(t, time.Second, exponential.DelayBefore(1))
+	assert.Equal(t, 2*time.Second, exponential.DelayBefore(2))
+	assert.Equal(t, 4*time.Second, exponential.DelayBefore(3))
+	assert.Equal(t, 5*time.Second, exponential.DelayBefore(4))
+
+	// A delay larger than max_delay is capped too
+	capped := &ast.Retries{Count: 3, Delay: 10 * time.Second, MaxDelay: 5 * time.Second}
+	assert.Equal(t, 5*time.Second, capped.DelayBefore(1))
+	capped.Backoff = ast.BackoffExponential
+	assert.Equal(t, 5*time.Second, capped.DelayBefore(1))
+	assert.Equal(t, 5*time.Second, capped.DelayBefore(3))
+}
+
+func TestRetriesShouldRetry(t *testing.T) {
+	t.Parallel()
+
+	retries := &ast.Retries{Count: 2, ExitCodes: []int{75}}
+	assert.True(t, retries.ShouldRetry(0, 75))
+	assert.True(t, retries.ShouldRetry(1, 75))
+	assert.False(t, retries.ShouldRetry(2, 75))
+	assert.False(t, retries.ShouldRetry(0, 1))
+
+	var none *ast.Retries
+	assert.False(t, none.ShouldRetry(0, 1))
+}
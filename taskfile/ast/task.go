@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -11,6 +12,14 @@ import (
 	"github.com/go-task/task/v3/internal/deepcopy"
 )
 
+// What a task does when one of its deps fails
+const (
+	// DepsFailureCancel cancels the other deps of the task
+	DepsFailureCancel = "cancel"
+	// DepsFailureContinue lets the other deps of the task finish
+	DepsFailureContinue = "continue"
+)
+
 // Task represents a task
 type Task struct {
 	Task          string
@@ -21,6 +30,11 @@ type Task struct {
 	Prompt        Prompt
 	Summary       string
 	Requires      *Requires
+	Retries       *Retries
+	Timeout       time.Duration
+	KillTimeout   time.Duration
+	DepsFailure   string
+	Fingerprint   *Fingerprint
 	Aliases       []string
 	Sources       []*Glob
 	Generates     []*Glob
@@ -46,6 +60,8 @@ type Task struct {
 	Namespace            string
 	IncludeVars          *Vars
 	IncludedTaskfileVars *Vars
+	// Populated during compilation
+	CallVars *Vars
 }
 
 func (t *Task) Name() string {
@@ -137,11 +153,21 @@ func (t *Task) UnmarshalYAML(node *yaml.Node) error {
 			Run           string
 			Platforms     []*Platform
 			Requires      *Requires
+			Retries       *Retries
+			Timeout       time.Duration
+			KillTimeout   time.Duration `yaml:"kill_timeout"`
+			DepsFailure   string        `yaml:"deps_failure"`
+			Fingerprint   *Fingerprint
 			Watch         bool
 		}
 		if err := node.Decode(&task); err != nil {
 			return errors.NewTaskfileDecodeError(err, node)
 		}
+		switch task.DepsFailure {
+		case "", DepsFailureCancel, DepsFailureContinue:
+		default:
+			return errors.NewTaskfileDecodeError(nil, node).WithMessage("invalid deps_failure %q, must be %q or %q", task.DepsFailure, DepsFailureCancel, DepsFailureContinue)
+		}
 		if task.Cmd != nil {
 			if task.Cmds != nil {
 				return errors.NewTaskfileDecodeError(nil, node).WithMessage("task cannot have both cmd and cmds")
@@ -175,6 +201,11 @@ func (t *Task) UnmarshalYAML(node *yaml.Node) error {
 		t.Run = task.Run
 		t.Platforms = task.Platforms
 		t.Requires = task.Requires
+		t.Retries = task.Retries
+		t.Timeout = task.Timeout
+		t.KillTimeout = task.KillTimeout
+		t.DepsFailure = task.DepsFailure
+		t.Fingerprint = task.Fingerprint
 		t.Watch = task.Watch
 		return nil
 	}
@@ -219,7 +250,13 @@ func (t *Task) DeepCopy() *Task {
 		Platforms:            deepcopy.Slice(t.Platforms),
 		Location:             t.Location.DeepCopy(),
 		Requires:             t.Requires.DeepCopy(),
+		Retries:              t.Retries.DeepCopy(),
+		Timeout:              t.Timeout,
+		KillTimeout:          t.KillTimeout,
+		DepsFailure:          t.DepsFailure,
+		Fingerprint:          t.Fingerprint.DeepCopy(),
 		Namespace:            t.Namespace,
+		CallVars:             t.CallVars.DeepCopy(),
 	}
 	return c
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/go-task/task/v3/errors"
 	"github.com/go-task/task/v3/internal/env"
 	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/schema"
 	"github.com/go-task/task/v3/internal/templater"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
@@ -27,6 +28,11 @@ Continue?`
 Continue?`
 )
 
+// ErrUnknownKey is wrapped by the [errors.TaskfileDecodeError] returned by a
+// strict [Reader] when a Taskfile contains a key that is not part of its
+// schema.
+var ErrUnknownKey = errors.New("unknown key")
+
 type (
 	// ReaderDebugFunc is a function that is called when the [Reader] wants to
 	// log debug messages
@@ -34,6 +40,14 @@ type (
 	// ReaderPromptFunc is a function that is called when the [Reader] wants to
 	// prompt the user in some way
 	ReaderPromptFunc func(string) error
+	// ReaderWarnFunc is a function that is called when the [Reader] wants to
+	// warn the user about something that doesn't stop the Taskfile from being
+	// read
+	ReaderWarnFunc func(string)
+	// ReaderTraceFunc is a function that is called when the [Reader] starts
+	// reading a remote Taskfile. The returned function is called when the read
+	// has finished.
+	ReaderTraceFunc func(location string) func()
 	// ReaderOption is a function that configures a [Reader].
 	ReaderOption func(*Reader)
 	// A Reader will recursively read Taskfiles from a given [Node] and build a
@@ -46,8 +60,12 @@ type (
 		offline     bool
 		timeout     time.Duration
 		tempDir     string
+		schema      *schema.Schema
+		strict      bool
 		debugFunc   ReaderDebugFunc
+		warnFunc    ReaderWarnFunc
 		promptFunc  ReaderPromptFunc
+		traceFunc   ReaderTraceFunc
 		promptMutex sync.Mutex
 	}
 )
@@ -66,8 +84,12 @@ func NewReader(
 		offline:     false,
 		timeout:     time.Second * 10,
 		tempDir:     os.TempDir(),
+		schema:      nil,
+		strict:      false,
 		debugFunc:   nil,
+		warnFunc:    nil,
 		promptFunc:  nil,
+		traceFunc:   nil,
 		promptMutex: sync.Mutex{},
 	}
 	r.Options(opts...)
@@ -133,6 +155,33 @@ func ReaderWithDebugFunc(debugFunc ReaderDebugFunc) ReaderOption {
 	}
 }
 
+// ReaderWithSchema sets the schema that the [Reader] uses to find keys in a
+// Taskfile that don't mean anything to Task, which are usually typos. Unknown
+// keys are passed to the warn function, or returned as errors if the [Reader]
+// is strict. By default, no schema is set and unknown keys are ignored.
+func ReaderWithSchema(schema *schema.Schema) ReaderOption {
+	return func(r *Reader) {
+		r.schema = schema
+	}
+}
+
+// ReaderWithStrict makes the [Reader] return an error when a Taskfile contains
+// a key that is not part of its schema instead of warning about it.
+func ReaderWithStrict(strict bool) ReaderOption {
+	return func(r *Reader) {
+		r.strict = strict
+	}
+}
+
+// ReaderWithWarnFunc sets the warn function to be used by the [Reader]. If set,
+// this function will be called with warnings about the Taskfiles being read.
+// By default, no warn function is set and warnings are not written.
+func ReaderWithWarnFunc(warnFunc ReaderWarnFunc) ReaderOption {
+	return func(r *Reader) {
+		r.warnFunc = warnFunc
+	}
+}
+
 // ReaderWithPromptFunc sets the prompt function to be used by the [Reader]. If
 // set, this function will be called with prompt messages. The function should
 // optionally log the message to the user and return nil if the prompt is
@@ -146,6 +195,16 @@ func ReaderWithPromptFunc(promptFunc ReaderPromptFunc) ReaderOption {
 	}
 }
 
+// ReaderWithTraceFunc sets the trace function to be used by the [Reader]. If
+// set, this function will be called whenever a remote Taskfile is read so that
+// the caller can measure how long it takes. By default, no trace function is
+// set.
+func ReaderWithTraceFunc(traceFunc ReaderTraceFunc) ReaderOption {
+	return func(r *Reader) {
+		r.traceFunc = traceFunc
+	}
+}
+
 // Read will read the Taskfile defined by the [Reader]'s [Node] and recurse
 // through any [ast.Includes] it finds, reading each included Taskfile and
 // building an [ast.TaskfileGraph] as it goes. If any errors occur, they will be
@@ -163,6 +222,19 @@ func (r *Reader) debugf(format string, a ...any) {
 	}
 }
 
+func (r *Reader) warnf(format string, a ...any) {
+	if r.warnFunc != nil {
+		r.warnFunc(fmt.Sprintf(format, a...))
+	}
+}
+
+func (r *Reader) trace(location string) func() {
+	if r.traceFunc == nil {
+		return func() {}
+	}
+	return r.traceFunc(location)
+}
+
 func (r *Reader) promptf(format string, a ...any) error {
 	if r.promptFunc != nil {
 		return r.promptFunc(fmt.Sprintf(format, a...))
@@ -306,6 +378,10 @@ func (r *Reader) readNode(node Node) (*ast.Taskfile, error) {
 		return nil, &errors.TaskfileVersionCheckError{URI: node.Location()}
 	}
 
+	if err := r.checkUnknownKeys(node, b); err != nil {
+		return nil, err
+	}
+
 	// Set the taskfile/task's locations
 	tf.Location = node.Location()
 	for task := range tf.Tasks.Values(nil) {
@@ -329,6 +405,8 @@ func (r *Reader) loadNodeContent(node Node) ([]byte, error) {
 		return node.Read(ctx)
 	}
 
+	defer r.trace(node.Location())()
+
 	cache, err := NewCache(r.tempDir)
 	if err != nil {
 		return nil, err
@@ -411,3 +489,46 @@ func (r *Reader) loadNodeContent(node Node) ([]byte, error) {
 
 	return b, nil
 }
+
+// checkUnknownKeys looks for keys in the Taskfile that are not part of the
+// schema. Task ignores these keys when decoding a Taskfile, so they are usually
+// typos that would otherwise go unnoticed.
+func (r *Reader) checkUnknownKeys(node Node, b []byte) error {
+	if r.schema == nil {
+		return nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil
+	}
+	for _, key := range r.schema.UnknownKeys(&doc) {
+		var didYouMean string
+		if suggestion := key.DidYouMean(); suggestion != "" {
+			didYouMean = fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+		snippet := NewSnippet(b,
+			SnippetWithLine(key.Node.Line),
+			SnippetWithColumn(key.Node.Column),
+			SnippetWithPadding(2),
+		)
+		if r.strict {
+			err := &errors.TaskfileDecodeError{
+				Line:   key.Node.Line,
+				Column: key.Node.Column,
+				Err:    ErrUnknownKey,
+			}
+			return err.
+				WithMessage("unknown key %q%s", key.Node.Value, didYouMean).
+				WithFileInfo(node.Location(), snippet.String())
+		}
+		r.warnf("task: Unknown key %q at %s:%d:%d%s\n%s\n",
+			key.Node.Value,
+			filepathext.TryAbsToRel(node.Location()),
+			key.Node.Line,
+			key.Node.Column,
+			didYouMean,
+			snippet,
+		)
+	}
+	return nil
+}
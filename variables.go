@@ -74,8 +74,14 @@ func (e *Executor) compiledTask(call *Call, evaluateShVars bool) (*ast.Task, err
 		Platforms:            origTask.Platforms,
 		Location:             origTask.Location,
 		Requires:             origTask.Requires,
+		Retries:              origTask.Retries,
+		Timeout:              origTask.Timeout,
+		KillTimeout:          origTask.KillTimeout,
+		DepsFailure:          origTask.DepsFailure,
+		Fingerprint:          origTask.Fingerprint,
 		Watch:                origTask.Watch,
 		Namespace:            origTask.Namespace,
+		CallVars:             call.Vars,
 	}
 	new.Dir, err = execext.Expand(new.Dir)
 	if err != nil {
@@ -129,9 +135,12 @@ func (e *Executor) compiledTask(call *Call, evaluateShVars bool) (*ast.Task, err
 	if len(origTask.Sources) > 0 && origTask.Method != "none" {
 		var checker fingerprint.SourcesCheckable
 
-		if origTask.Method == "timestamp" {
+		switch origTask.Method {
+		case "timestamp":
 			checker = fingerprint.NewTimestampChecker(e.TempDir.Fingerprint, e.Dry)
-		} else {
+		case "git":
+			checker = fingerprint.NewGitChecker(e.TempDir.Fingerprint, e.Dry)
+		default:
 			checker = fingerprint.NewChecksumChecker(e.TempDir.Fingerprint, e.Dry)
 		}
 
@@ -139,7 +148,12 @@ func (e *Executor) compiledTask(call *Call, evaluateShVars bool) (*ast.Task, err
 		if err != nil {
 			return nil, err
 		}
-		vars.Set(strings.ToUpper(checker.Kind()), ast.Var{Live: value})
+		name := strings.ToUpper(checker.Kind())
+		if name == "GIT" {
+			// The git method computes a checksum of the sources too
+			name = "CHECKSUM"
+		}
+		vars.Set(name, ast.Var{Live: value})
 
 		// Adding new variables, requires us to refresh the templaters
 		// cache of the the values manually